@@ -0,0 +1,27 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/tquid/pokedexcli/internal/fakepokeapi"
+)
+
+func main() {
+	addr := flag.String("addr", "localhost:8080", "address to listen on")
+	flag.Parse()
+
+	s, err := fakepokeapi.New()
+	if err != nil {
+		fmt.Printf("Error loading data: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Serving fake PokeAPI at http://%s/api/v2\n", *addr)
+	err = http.ListenAndServe(*addr, s)
+	if err != nil {
+		fmt.Printf("Error serving: %v\n", err)
+		os.Exit(1)
+	}
+}
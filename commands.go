@@ -0,0 +1,836 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/tquid/pokedexcli/internal/output"
+	"github.com/tquid/pokedexcli/internal/pokeapi"
+)
+
+type cliCommand struct {
+	name        string
+	description string
+	// usage is the argument syntax shown after the name, e.g. "<pokemon>".
+	usage    string
+	aliases  []string
+	examples []string
+	callback func(context.Context, []string) error
+}
+
+func initCommands(client *pokeapi.Client) map[string]cliCommand {
+	cmds := map[string]cliCommand{
+		"bag": {
+			name:        "bag",
+			description: "List the items you carry",
+			callback:    func(ctx context.Context, _ []string) error { return commandBag(ctx, client) },
+		},
+		"box": {
+			name:        "box",
+			description: "List every Pokemon you own",
+			callback:    func(context.Context, []string) error { return commandBox(client) },
+		},
+		"battle": {
+			name:        "battle",
+			description: "Fight the wild Pokemon you encountered, to weaken it before catching it",
+			usage:       "[#id]",
+			examples:    []string{"battle", "battle #3"},
+			callback:    func(ctx context.Context, params []string) error { return commandBattle(ctx, client, params) },
+		},
+		"catch": {
+			name:        "catch",
+			description: "Throw a ball from your bag at the wild Pokemon you encountered",
+			usage:       "<pokemon> [ball]",
+			examples:    []string{"catch bidoof", "catch bidoof great-ball"},
+			callback:    func(ctx context.Context, params []string) error { return commandCatch(ctx, client, params) },
+		},
+		"inspect": {
+			name:        "inspect",
+			description: "Show details of Pokemon you own, by species, nickname or ID",
+			usage:       "<pokemon|nickname|#id>",
+			examples:    []string{"inspect pikachu", "inspect #3"},
+			callback:    func(_ context.Context, params []string) error { return commandInspect(client, params) },
+		},
+		"encounter": {
+			name:        "encounter",
+			description: "Look for a wild Pokemon in the area you explored last",
+			usage:       "[method] [version]",
+			aliases:     []string{"walk"},
+			examples:    []string{"encounter", "encounter old-rod", "encounter surf platinum"},
+			callback:    func(ctx context.Context, params []string) error { return commandEncounter(ctx, client, params) },
+		},
+		"exit": {
+			name:        "exit",
+			description: "Save and exit the Pokedex",
+			aliases:     []string{"quit"},
+			callback:    func(context.Context, []string) error { return commandExit() },
+		},
+		"explore": {
+			name:        "explore",
+			description: "List the Pokemon found in an area",
+			usage:       "<area>",
+			examples:    []string{"explore canalave-city-area"},
+			callback:    func(ctx context.Context, params []string) error { return commandExplore(ctx, client, params) },
+		},
+		"load": {
+			name:        "load",
+			description: "Load a saved Pokedex",
+			usage:       "<file>",
+			examples:    []string{"load pokedex.json"},
+			callback:    func(_ context.Context, params []string) error { return commandLoad(client, params) },
+		},
+		"map": {
+			name:        "map",
+			description: "Show the next 20 map locations",
+			callback:    func(ctx context.Context, _ []string) error { return commandMap(ctx, client) },
+		},
+		"mapb": {
+			name:        "mapb",
+			description: "Show the previous 20 map locations",
+			callback:    func(ctx context.Context, _ []string) error { return commandMapb(ctx, client) },
+		},
+		"matchup": {
+			name:        "matchup",
+			description: "Show what a Pokemon's types are weak to, resist and are immune to, or compare two",
+			usage:       "<pokemon> [vs <pokemon>]",
+			examples:    []string{"matchup gyarados", "matchup pikachu vs magikarp"},
+			callback:    func(ctx context.Context, params []string) error { return commandMatchup(ctx, client, params) },
+		},
+		"nickname": {
+			name:        "nickname",
+			description: "Give a Pokemon you own a nickname, or clear it",
+			usage:       "<#id> [nickname]",
+			examples:    []string{"nickname #3 Sparky", "nickname #3"},
+			callback:    func(_ context.Context, params []string) error { return commandNickname(client, params) },
+		},
+		"output": {
+			name:        "output",
+			description: "Show or set the output format",
+			usage:       "[text|json|yaml]",
+			examples:    []string{"output", "output json"},
+			callback:    func(_ context.Context, params []string) error { return commandOutput(params) },
+		},
+		"pokedex": {
+			name:        "pokedex",
+			description: "List the species you have seen and caught",
+			aliases:     []string{"dex"},
+			callback:    func(context.Context, []string) error { return commandPokedex(client) },
+		},
+		"progress": {
+			name:        "progress",
+			description: "Show how much of a regional or national dex you have seen and caught",
+			usage:       "[dex]",
+			examples:    []string{"progress", "progress kanto"},
+			callback:    func(ctx context.Context, params []string) error { return commandProgress(ctx, client, params) },
+		},
+		"seed": {
+			name:        "seed",
+			description: "Show the random seed, or restart randomness from a new one",
+			usage:       "[seed]",
+			examples:    []string{"seed", "seed 42"},
+			callback:    func(_ context.Context, params []string) error { return commandSeed(client, params) },
+		},
+		"save": {
+			name:        "save",
+			description: "Save your Pokedex",
+			callback:    func(context.Context, []string) error { return commandSave(client) },
+		},
+	}
+	// help reads the registry, so it's added once the rest exists.
+	cmds["help"] = cliCommand{
+		name:        "help",
+		description: "List commands, or show how to use one",
+		usage:       "[command]",
+		aliases:     []string{"?"},
+		examples:    []string{"help", "help catch"},
+		callback:    func(_ context.Context, params []string) error { return commandHelp(cmds, params) },
+	}
+	return cmds
+}
+
+// initBattleCommands returns the commands available during a battle, which
+// replace the usual ones until it's over.
+func initBattleCommands(client *pokeapi.Client) map[string]cliCommand {
+	cmds := map[string]cliCommand{
+		"bag": {
+			name:        "bag",
+			description: "List the items you carry",
+			callback:    func(ctx context.Context, _ []string) error { return commandBag(ctx, client) },
+		},
+		"box": {
+			name:        "box",
+			description: "List every Pokemon you own, to find one to switch to",
+			callback:    func(context.Context, []string) error { return commandBox(client) },
+		},
+		"catch": {
+			name:        "catch",
+			description: "Throw a ball at the wild Pokemon; the weaker it is, the better your chances",
+			usage:       "[ball]",
+			examples:    []string{"catch", "catch ultra-ball"},
+			callback:    func(ctx context.Context, params []string) error { return commandBattleCatch(ctx, client, params) },
+		},
+		"exit": {
+			name:        "exit",
+			description: "Save and exit the Pokedex",
+			aliases:     []string{"quit"},
+			callback:    func(context.Context, []string) error { return commandExit() },
+		},
+		"fight": {
+			name:        "fight",
+			description: "Attack with one of your Pokemon's moves",
+			usage:       "<move>",
+			examples:    []string{"fight thunder-shock"},
+			callback:    func(ctx context.Context, params []string) error { return commandFight(ctx, client, params) },
+		},
+		"run": {
+			name:        "run",
+			description: "Run away from the battle",
+			callback:    func(context.Context, []string) error { return commandRun(client) },
+		},
+		"status": {
+			name:        "status",
+			description: "Show both Pokemon's HP and your Pokemon's moves",
+			callback:    func(context.Context, []string) error { return commandBattleStatus(client) },
+		},
+		"switch": {
+			name:        "switch",
+			description: "Send out another Pokemon you own",
+			usage:       "<#id>",
+			examples:    []string{"switch #3"},
+			callback:    func(ctx context.Context, params []string) error { return commandSwitch(ctx, client, params) },
+		},
+	}
+	cmds["help"] = cliCommand{
+		name:        "help",
+		description: "List battle commands, or show how to use one",
+		usage:       "[command]",
+		aliases:     []string{"?"},
+		examples:    []string{"help", "help fight"},
+		callback:    func(_ context.Context, params []string) error { return commandHelp(cmds, params) },
+	}
+	return cmds
+}
+
+// lookupCommand finds a command by name or alias.
+func lookupCommand(cmds map[string]cliCommand, name string) (cliCommand, bool) {
+	if cmd, ok := cmds[name]; ok {
+		return cmd, true
+	}
+	for _, cmd := range cmds {
+		if slices.Contains(cmd.aliases, name) {
+			return cmd, true
+		}
+	}
+	return cliCommand{}, false
+}
+
+func commandCatch(ctx context.Context, c *pokeapi.Client, params []string) error {
+	if len(params) == 0 {
+		return fmt.Errorf("'catch' command requires a pokemon name, e.g. 'catch pikachu'")
+	}
+	pokemonName := params[0]
+	wild, err := c.WildNamed(pokemonName)
+	var notFound *pokeapi.NotFoundError
+	if errors.As(err, &notFound) && len(notFound.Suggestions) == 0 {
+		return fmt.Errorf("there's no wild %s here, use 'encounter' to find one", pokemonName)
+	}
+	if err != nil {
+		// A typo of the wild Pokemon's name, which the REPL offers to fix.
+		return err
+	}
+	ball := pokeapi.PokeBall
+	if len(params) > 1 {
+		var ok bool
+		ball, ok = pokeapi.BallByName(params[1])
+		if !ok {
+			return fmt.Errorf("'%s' isn't a ball, try one of %s", params[1], strings.Join(ballNames(), ", "))
+		}
+	}
+	return throwBall(ctx, c, wild.Pokemon, ball)
+}
+
+// commandBattleCatch is catch during a battle, where there's only one
+// Pokemon to throw at.
+func commandBattleCatch(ctx context.Context, c *pokeapi.Client, params []string) error {
+	wild, ok := c.Wild()
+	if !ok {
+		return pokeapi.ErrNoWild
+	}
+	// Allow 'catch magikarp' out of habit.
+	if len(params) > 0 && params[0] == wild.Pokemon {
+		params = params[1:]
+	}
+	ball := pokeapi.PokeBall
+	if len(params) > 0 {
+		ball, ok = pokeapi.BallByName(params[0])
+		if !ok {
+			return fmt.Errorf("'%s' isn't a ball, try one of %s", params[0], strings.Join(ballNames(), ", "))
+		}
+	}
+	return throwBall(ctx, c, wild.Pokemon, ball)
+}
+
+func throwBall(ctx context.Context, c *pokeapi.Client, pokemonName string, ball pokeapi.Ball) error {
+	inBattle := c.InBattle()
+	catch, err := c.CatchWildContext(ctx, ball)
+	if err != nil {
+		return fmt.Errorf("error catching %s: %w", pokemonName, err)
+	}
+	result := catchResult{
+		Pokemon:   pokemonName,
+		Ball:      ball.Name,
+		BallName:  ball.Name,
+		BallsLeft: c.Bag()[ball.Name],
+		Caught:    catch.Caught,
+		Shakes:    catch.Shakes,
+	}
+	// The bag already fetched ball names, so this is normally cached.
+	if item, err := c.GetItemContext(ctx, ball.Name); err == nil {
+		result.BallName = item.DisplayName()
+	}
+	if result.Caught {
+		owned := ownedResultFrom(catch.Owned)
+		result.Owned = &owned
+		err = c.Save()
+		if err != nil {
+			return fmt.Errorf("error saving pokedex: %w", err)
+		}
+	} else if inBattle {
+		turn := turnResultFrom(c, catch.Turn)
+		result.Turn = &turn
+	}
+	return out.Print(result)
+}
+
+func commandBattle(ctx context.Context, c *pokeapi.Client, params []string) error {
+	var id int
+	if len(params) > 0 {
+		var err error
+		id, err = parseOwnedID(params[0])
+		if err != nil {
+			return err
+		}
+	}
+	status, err := c.StartBattleContext(ctx, id)
+	if errors.Is(err, pokeapi.ErrNoWild) {
+		return fmt.Errorf("there's no wild pokemon to fight, use 'encounter' to find one")
+	}
+	if err != nil {
+		return fmt.Errorf("starting battle: %w", err)
+	}
+	return out.Print(battleResult{
+		Wild:    battlerResultFrom(status.Wild),
+		Active:  battlerResultFrom(status.Active),
+		Started: true,
+	})
+}
+
+func commandBattleStatus(c *pokeapi.Client) error {
+	status, ok := c.Battle()
+	if !ok {
+		return pokeapi.ErrNoBattle
+	}
+	return out.Print(battleResult{
+		Wild:   battlerResultFrom(status.Wild),
+		Active: battlerResultFrom(status.Active),
+	})
+}
+
+func commandFight(ctx context.Context, c *pokeapi.Client, params []string) error {
+	if len(params) == 0 {
+		return fmt.Errorf("'fight' command requires a move, e.g. 'fight tackle', see 'status' for moves")
+	}
+	turn, err := c.FightContext(ctx, params[0])
+	if err != nil {
+		return err
+	}
+	return out.Print(turnResultFrom(c, turn))
+}
+
+func commandSwitch(ctx context.Context, c *pokeapi.Client, params []string) error {
+	if len(params) == 0 {
+		return fmt.Errorf("'switch' command requires a pokemon ID, e.g. 'switch #3', see 'box' for IDs")
+	}
+	id, err := parseOwnedID(params[0])
+	if err != nil {
+		return err
+	}
+	turn, err := c.SwitchContext(ctx, id)
+	if err != nil {
+		return err
+	}
+	result := switchResult{}
+	if status, ok := c.Battle(); ok {
+		result.Pokemon = battlerResultFrom(status.Active)
+	}
+	if len(turn.Events) > 0 || turn.Outcome != pokeapi.BattleOngoing {
+		t := turnResultFrom(c, turn)
+		result.Turn = &t
+	}
+	return out.Print(result)
+}
+
+func commandRun(c *pokeapi.Client) error {
+	err := c.Run()
+	if err != nil {
+		return err
+	}
+	return out.Print(messageResult{Message: "Got away safely!"})
+}
+
+func battlerResultFrom(b pokeapi.Battler) battlerResult {
+	result := battlerResult{
+		Name:    b.Name,
+		Species: b.Species,
+		ID:      b.OwnedID,
+		Level:   b.Level,
+		HP:      b.HP,
+		MaxHP:   b.MaxHP,
+		Types:   nonNil(b.Types),
+		Moves:   []moveResult{},
+		Status:  string(b.Status),
+	}
+	for _, m := range b.Moves {
+		result.Moves = append(result.Moves, moveResult{
+			Name:        m.Name,
+			Type:        m.Type.Name,
+			DamageClass: m.DamageClass.Name,
+			Power:       m.Power,
+			Accuracy:    m.Accuracy,
+			PP:          m.PP,
+		})
+	}
+	return result
+}
+
+// turnResultFrom converts t, checking whether your Pokemon fainted and
+// needs replacing.
+func turnResultFrom(c *pokeapi.Client, t pokeapi.TurnResult) turnResult {
+	result := turnResult{
+		Events:  []battleEventResult{},
+		Outcome: string(t.Outcome),
+	}
+	for _, e := range t.Events {
+		result.Events = append(result.Events, battleEventResult{
+			Attacker:      e.Attacker,
+			Target:        e.Target,
+			Move:          e.Move,
+			Missed:        e.Missed,
+			Damage:        e.Damage,
+			Effectiveness: e.Effectiveness,
+			TargetHP:      e.TargetHP,
+			TargetMaxHP:   e.TargetMaxHP,
+			Fainted:       e.Fainted,
+			Status:        string(e.Status),
+		})
+	}
+	if status, ok := c.Battle(); ok && status.Active.Fainted() {
+		result.MustSwitch = true
+	}
+	return result
+}
+
+func commandEncounter(ctx context.Context, c *pokeapi.Client, params []string) error {
+	var method, version string
+	if len(params) > 0 {
+		method = params[0]
+	}
+	if len(params) > 1 {
+		version = params[1]
+	}
+	wild, err := c.EncounterContext(ctx, method, version)
+	if errors.Is(err, pokeapi.ErrNoArea) {
+		return fmt.Errorf("explore an area first, e.g. 'explore eterna-forest-area'")
+	}
+	if err != nil {
+		return fmt.Errorf("looking for wild pokemon: %w", err)
+	}
+	return out.Print(encounterResult{
+		Pokemon: wild.Pokemon,
+		Level:   wild.Level,
+		Method:  wild.Method,
+		Version: wild.Version,
+		Area:    wild.Area,
+	})
+}
+
+func commandInspect(c *pokeapi.Client, params []string) error {
+	if len(params) == 0 {
+		return fmt.Errorf("'inspect' command requires a pokemon name, e.g. 'inspect pikachu'")
+	}
+	owned := c.FindOwned(params[0])
+	if len(owned) == 0 {
+		return fmt.Errorf("you don't own %s (or it doesn't exist)", params[0])
+	}
+	// Every match is the same species unless a nickname is shared across
+	// species, so describe the first match's species.
+	entry, ok := c.GetPokedexEntry(owned[0].Species)
+	if !ok || entry.Pokemon == nil {
+		return fmt.Errorf("no pokedex data for %s", owned[0].Species)
+	}
+	pokemon := entry.Pokemon
+	result := inspectResult{
+		Name:   pokemon.Name,
+		Height: pokemon.Height,
+		Weight: pokemon.Weight,
+		Stats:  []statResult{},
+		Types:  []string{},
+		Owned:  []ownedResult{},
+	}
+	for _, o := range owned {
+		result.Owned = append(result.Owned, ownedResultFrom(o))
+	}
+	for _, stat := range pokemon.Stats {
+		result.Stats = append(result.Stats, statResult{Name: stat.Stat.Name, BaseStat: stat.BaseStat})
+	}
+	for _, pokemonType := range pokemon.Types {
+		result.Types = append(result.Types, pokemonType.Type.Name)
+	}
+	return out.Print(result)
+}
+
+func commandPokedex(c *pokeapi.Client) error {
+	result := pokedexResult{Pokemon: []pokedexEntryResult{}}
+	owned := make(map[string]int)
+	for _, o := range c.Box() {
+		owned[o.Species]++
+	}
+	for _, entry := range c.PokedexEntries() {
+		result.Pokemon = append(result.Pokemon, pokedexEntryResult{
+			Name:   entry.Name,
+			Seen:   entry.Seen,
+			Caught: entry.Caught,
+			Owned:  owned[entry.Name],
+		})
+	}
+	return out.Print(result)
+}
+
+// defaultDex is what progress measures against when no dex is named.
+const defaultDex = "national"
+
+func commandProgress(ctx context.Context, c *pokeapi.Client, params []string) error {
+	dexName := defaultDex
+	if len(params) > 0 {
+		dexName = params[0]
+	}
+	p, err := c.ProgressContext(ctx, dexName)
+	if err != nil {
+		return fmt.Errorf("getting %s dex: %w", dexName, err)
+	}
+	return out.Print(progressResult{
+		Dex:        p.Dex,
+		Total:      p.Total,
+		Seen:       p.Seen,
+		Caught:     p.Caught,
+		Completion: percent(p.Caught, p.Total),
+	})
+}
+
+// percent rounds to one decimal place so 1 of 151 isn't shown as 0%.
+func percent(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(n)*1000/float64(total)) / 10
+}
+
+func commandMatchup(ctx context.Context, c *pokeapi.Client, params []string) error {
+	switch {
+	case len(params) == 1:
+		return matchupOne(ctx, c, params[0])
+	case len(params) == 3 && params[1] == "vs":
+		return matchupVersus(ctx, c, params[0], params[2])
+	}
+	return fmt.Errorf("'matchup' command requires a pokemon name, e.g. 'matchup pikachu' or 'matchup pikachu vs magikarp'")
+}
+
+func pokemonTypes(p pokeapi.Pokemon) []string {
+	types := []string{}
+	for _, t := range p.Types {
+		types = append(types, t.Type.Name)
+	}
+	return types
+}
+
+func matchupOne(ctx context.Context, c *pokeapi.Client, name string) error {
+	pokemon, err := c.GetPokemonContext(ctx, name)
+	if err != nil {
+		return fmt.Errorf("getting pokemon %s: %w", name, err)
+	}
+	types := pokemonTypes(pokemon)
+	taken, err := c.DamageTakenContext(ctx, types)
+	if err != nil {
+		return fmt.Errorf("getting type chart: %w", err)
+	}
+	result := matchupResult{
+		Pokemon:     pokemon.Name,
+		Types:       types,
+		Weaknesses:  []typeMultiplierResult{},
+		Resistances: []typeMultiplierResult{},
+		Immunities:  []string{},
+	}
+	// Strongest effect first, so a 4x weakness leads.
+	attackTypes := slices.SortedFunc(maps.Keys(taken), func(a, b string) int {
+		return cmp.Or(cmp.Compare(math.Abs(math.Log2(taken[b])), math.Abs(math.Log2(taken[a]))), strings.Compare(a, b))
+	})
+	for _, attackType := range attackTypes {
+		m := taken[attackType]
+		switch {
+		case m == 0:
+			result.Immunities = append(result.Immunities, attackType)
+		case m > 1:
+			result.Weaknesses = append(result.Weaknesses, typeMultiplierResult{Type: attackType, Multiplier: m})
+		default:
+			result.Resistances = append(result.Resistances, typeMultiplierResult{Type: attackType, Multiplier: m})
+		}
+	}
+	return out.Print(result)
+}
+
+func matchupVersus(ctx context.Context, c *pokeapi.Client, firstName, secondName string) error {
+	first, err := c.GetPokemonContext(ctx, firstName)
+	if err != nil {
+		return fmt.Errorf("getting pokemon %s: %w", firstName, err)
+	}
+	second, err := c.GetPokemonContext(ctx, secondName)
+	if err != nil {
+		return fmt.Errorf("getting pokemon %s: %w", secondName, err)
+	}
+	firstSide, err := versusSide(ctx, c, first, second)
+	if err != nil {
+		return err
+	}
+	secondSide, err := versusSide(ctx, c, second, first)
+	if err != nil {
+		return err
+	}
+	result := versusResult{First: firstSide, Second: secondSide}
+	switch {
+	case firstSide.Best > secondSide.Best:
+		result.Advantage = first.Name
+	case secondSide.Best > firstSide.Best:
+		result.Advantage = second.Name
+	}
+	return out.Print(result)
+}
+
+// versusSide rates attacker's same-type moves, the ones it hits hardest
+// with, against defender.
+func versusSide(ctx context.Context, c *pokeapi.Client, attacker, defender pokeapi.Pokemon) (versusSideResult, error) {
+	side := versusSideResult{
+		Pokemon: attacker.Name,
+		Types:   pokemonTypes(attacker),
+		Attacks: []typeMultiplierResult{},
+	}
+	for _, attackType := range side.Types {
+		m, err := c.EffectivenessContext(ctx, attackType, pokemonTypes(defender))
+		if err != nil {
+			return versusSideResult{}, fmt.Errorf("getting type chart: %w", err)
+		}
+		side.Attacks = append(side.Attacks, typeMultiplierResult{Type: attackType, Multiplier: m})
+		side.Best = max(side.Best, m)
+	}
+	return side, nil
+}
+
+func ballNames() []string {
+	var names []string
+	for _, ball := range pokeapi.Balls {
+		names = append(names, ball.Name)
+	}
+	return names
+}
+
+func ballRank(name string) int {
+	i := slices.IndexFunc(pokeapi.Balls, func(b pokeapi.Ball) bool { return b.Name == name })
+	if i < 0 {
+		return len(pokeapi.Balls)
+	}
+	return i
+}
+
+func commandBag(ctx context.Context, c *pokeapi.Client) error {
+	result := bagResult{Items: []bagItemResult{}}
+	bag := c.Bag()
+	// Balls come first, weakest first, like in the games.
+	names := slices.SortedFunc(maps.Keys(bag), func(a, b string) int {
+		return cmp.Or(cmp.Compare(ballRank(a), ballRank(b)), strings.Compare(a, b))
+	})
+	for _, name := range names {
+		item, err := c.GetItemContext(ctx, name)
+		if err != nil {
+			return fmt.Errorf("getting item %s: %w", name, err)
+		}
+		entry := bagItemResult{
+			Name:        name,
+			DisplayName: item.DisplayName(),
+			Description: item.Description(),
+			Count:       bag[name],
+		}
+		if ball, ok := pokeapi.BallByName(name); ok {
+			entry.CatchBonus = ball.Bonus
+		}
+		result.Items = append(result.Items, entry)
+	}
+	return out.Print(result)
+}
+
+func commandBox(c *pokeapi.Client) error {
+	result := boxResult{Pokemon: []ownedResult{}}
+	for _, o := range c.Box() {
+		result.Pokemon = append(result.Pokemon, ownedResultFrom(o))
+	}
+	return out.Print(result)
+}
+
+func commandNickname(c *pokeapi.Client, params []string) error {
+	if len(params) == 0 {
+		return fmt.Errorf("'nickname' command requires a pokemon ID, e.g. 'nickname #3 Sparky'")
+	}
+	id, err := parseOwnedID(params[0])
+	if err != nil {
+		return err
+	}
+	owned, err := c.SetNickname(id, strings.Join(params[1:], " "))
+	if err != nil {
+		return err
+	}
+	err = c.Save()
+	if err != nil {
+		return fmt.Errorf("error saving pokedex: %w", err)
+	}
+	if owned.Nickname == "" {
+		return out.Print(messageResult{Message: fmt.Sprintf("#%d is just %s again", owned.ID, owned.Species)})
+	}
+	return out.Print(messageResult{Message: fmt.Sprintf("#%d %s is now called %s", owned.ID, owned.Species, owned.Nickname)})
+}
+
+// parseOwnedID reads a box ID, with or without the leading #.
+func parseOwnedID(param string) (int, error) {
+	id, err := strconv.Atoi(strings.TrimPrefix(param, "#"))
+	if err != nil {
+		return 0, fmt.Errorf("'%s' isn't a pokemon ID, see 'box' for IDs", param)
+	}
+	return id, nil
+}
+
+func commandHelp(cmds map[string]cliCommand, params []string) error {
+	if len(params) > 0 {
+		cmd, ok := lookupCommand(cmds, params[0])
+		if !ok {
+			return fmt.Errorf("unknown command '%s'", params[0])
+		}
+		return out.Print(commandHelpResult{Command: helpEntry(cmd)})
+	}
+	result := helpResult{Commands: []helpEntryResult{}}
+	for _, name := range slices.Sorted(maps.Keys(cmds)) {
+		result.Commands = append(result.Commands, helpEntry(cmds[name]))
+	}
+	return out.Print(result)
+}
+
+func helpEntry(cmd cliCommand) helpEntryResult {
+	return helpEntryResult{
+		Name:        cmd.name,
+		Usage:       strings.TrimSpace(cmd.name + " " + cmd.usage),
+		Description: cmd.description,
+		Aliases:     nonNil(cmd.aliases),
+		Examples:    nonNil(cmd.examples),
+	}
+}
+
+// errExit tells the session to stop reading commands; it saves on the way
+// out.
+var errExit = errors.New("exit")
+
+func commandExit() error {
+	return errExit
+}
+
+func commandSave(c *pokeapi.Client) error {
+	err := c.Save()
+	if err != nil {
+		return fmt.Errorf("error saving pokedex: %w", err)
+	}
+	return out.Print(messageResult{Message: fmt.Sprintf("Pokedex saved to %s", c.SavePath())})
+}
+
+func commandLoad(c *pokeapi.Client, params []string) error {
+	if len(params) == 0 {
+		return fmt.Errorf("'load' command requires a file name, e.g. 'load pokedex.json'")
+	}
+	err := c.LoadFrom(params[0])
+	if err != nil {
+		return fmt.Errorf("error loading pokedex: %w", err)
+	}
+	return out.Print(messageResult{Message: fmt.Sprintf("Pokedex loaded from %s", params[0])})
+}
+
+func commandMap(ctx context.Context, c *pokeapi.Client) error {
+	err := c.NextLocationAreasContext(ctx)
+	if err != nil {
+		return fmt.Errorf("getting next map chunk: %w", err)
+	}
+	return out.Print(locationsResult{Locations: nonNil(c.GetLocationNames())})
+}
+
+func commandMapb(ctx context.Context, c *pokeapi.Client) error {
+	err := c.PreviousLocationAreasContext(ctx)
+	if err != nil {
+		return fmt.Errorf("getting previous map chunk: %w", err)
+	}
+	return out.Print(locationsResult{Locations: nonNil(c.GetLocationNames())})
+}
+
+func commandExplore(ctx context.Context, c *pokeapi.Client, params []string) error {
+	var areaName string
+	if len(params) == 0 {
+		return fmt.Errorf("'explore' command requires an area name, e.g. 'explore canalave-city-area'")
+	}
+	areaName = params[0]
+	pokemonList, err := c.ExploreAreaContext(ctx, areaName)
+	if err != nil {
+		return fmt.Errorf("exploring area %s: %w", areaName, err)
+	}
+	return out.Print(exploreResult{Area: areaName, Pokemon: nonNil(pokemonList)})
+}
+
+func commandSeed(c *pokeapi.Client, params []string) error {
+	if len(params) == 0 {
+		return out.Print(seedResult{Seed: c.Seed()})
+	}
+	seed, err := strconv.ParseInt(params[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("'%s' isn't a seed, it must be a whole number", params[0])
+	}
+	c.SetSeed(seed)
+	return out.Print(seedResult{Seed: seed, Set: true})
+}
+
+func commandOutput(params []string) error {
+	if len(params) == 0 {
+		return out.Print(messageResult{Message: fmt.Sprintf("Output format is %s", out.Format())})
+	}
+	format, err := output.ParseFormat(params[0])
+	if err != nil {
+		return err
+	}
+	out.SetFormat(format)
+	return out.Print(messageResult{Message: fmt.Sprintf("Output format set to %s", format)})
+}
+
+// nonNil makes sure empty lists encode as [] rather than null.
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
@@ -1,3 +1,7 @@
 module github.com/tquid/pokedexcli
 
 go 1.23.1
+
+require golang.org/x/term v0.32.0
+
+require golang.org/x/sys v0.33.0 // indirect
@@ -0,0 +1,174 @@
+// Package fakepokeapi serves the subset of PokeAPI v2 that the pokeapi
+// client uses, from JSON embedded in the binary, so tests and demos can run
+// without network access.
+package fakepokeapi
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//go:embed data
+var data embed.FS
+
+const (
+	apiPrefix    = "/api/v2/"
+	defaultLimit = 20
+)
+
+type resource struct {
+	name string
+	body []byte
+	id   int
+}
+
+type Server struct {
+	// resources maps a resource type like "pokemon" to its entries,
+	// ordered by id as PokeAPI lists them.
+	resources map[string][]resource
+}
+
+// New loads the embedded data. Each file data/<type>/<name>.json is served
+// at /api/v2/<type>/<name> and listed at /api/v2/<type>.
+func New() (*Server, error) {
+	s := &Server{resources: make(map[string][]resource)}
+	err := fs.WalkDir(data, "data", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || path.Ext(p) != ".json" {
+			return err
+		}
+		body, err := data.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		var meta struct {
+			ID int `json:"id"`
+		}
+		err = json.Unmarshal(body, &meta)
+		if err != nil {
+			return fmt.Errorf("can't unmarshal %s: %w", p, err)
+		}
+		kind := path.Base(path.Dir(p))
+		s.resources[kind] = append(s.resources[kind], resource{
+			name: strings.TrimSuffix(path.Base(p), ".json"),
+			body: body,
+			id:   meta.ID,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("can't load embedded data: %w", err)
+	}
+	for _, entries := range s.resources {
+		sort.Slice(entries, func(i, j int) bool { return entries[i].id < entries[j].id })
+	}
+	return s, nil
+}
+
+// NewTestServer starts an httptest.Server backed by the embedded data. Point
+// a client at its URL plus "/api/v2".
+func NewTestServer() (*httptest.Server, error) {
+	s, err := New()
+	if err != nil {
+		return nil, err
+	}
+	return httptest.NewServer(s), nil
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	rest, ok := strings.CutPrefix(r.URL.Path, apiPrefix)
+	if !ok {
+		notFound(w)
+		return
+	}
+	kind, name, _ := strings.Cut(strings.Trim(rest, "/"), "/")
+	entries, ok := s.resources[kind]
+	if !ok {
+		notFound(w)
+		return
+	}
+	if name == "" {
+		s.serveList(w, r, kind, entries)
+		return
+	}
+	for _, entry := range entries {
+		if entry.name == name || strconv.Itoa(entry.id) == name {
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			w.Write(entry.body)
+			return
+		}
+	}
+	notFound(w)
+}
+
+// notFound mimics PokeAPI, which answers unknown names with a plain text
+// body rather than JSON.
+func notFound(w http.ResponseWriter) {
+	http.Error(w, "Not Found", http.StatusNotFound)
+}
+
+type namedResource struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+type listPage struct {
+	Count    int             `json:"count"`
+	Next     *string         `json:"next"`
+	Previous *string         `json:"previous"`
+	Results  []namedResource `json:"results"`
+}
+
+func queryInt(r *http.Request, key string, fallback int) int {
+	n, err := strconv.Atoi(r.URL.Query().Get(key))
+	if err != nil || n < 0 {
+		return fallback
+	}
+	return n
+}
+
+func (s *Server) serveList(w http.ResponseWriter, r *http.Request, kind string, entries []resource) {
+	offset := queryInt(r, "offset", 0)
+	limit := queryInt(r, "limit", defaultLimit)
+	if limit == 0 {
+		limit = defaultLimit
+	}
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	base := fmt.Sprintf("%s://%s%s%s", scheme, r.Host, apiPrefix, kind)
+	pageURL := func(offset int) *string {
+		u := fmt.Sprintf("%s?offset=%d&limit=%d", base, offset, limit)
+		return &u
+	}
+
+	page := listPage{Count: len(entries), Results: []namedResource{}}
+	for i := offset; i < len(entries) && i < offset+limit; i++ {
+		page.Results = append(page.Results, namedResource{
+			Name: entries[i].name,
+			URL:  fmt.Sprintf("%s/%d/", base, entries[i].id),
+		})
+	}
+	if offset+limit < len(entries) {
+		page.Next = pageURL(offset + limit)
+	}
+	if offset > 0 {
+		page.Previous = pageURL(max(offset-limit, 0))
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.Encode(page)
+}
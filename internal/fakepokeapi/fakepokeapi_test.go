@@ -0,0 +1,87 @@
+package fakepokeapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestServer(t *testing.T) {
+	server, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer server.Close()
+
+	cases := []struct {
+		path       string
+		wantStatus int
+	}{
+		{path: "/api/v2/pokemon/pikachu", wantStatus: http.StatusOK},
+		{path: "/api/v2/pokemon/25", wantStatus: http.StatusOK},
+		{path: "/api/v2/pokemon/missingno", wantStatus: http.StatusNotFound},
+		{path: "/api/v2/location-area/canalave-city-area", wantStatus: http.StatusOK},
+		{path: "/api/v2/location-area", wantStatus: http.StatusOK},
+		{path: "/api/v2/pokedex/kanto", wantStatus: http.StatusOK},
+		{path: "/api/v2/pokedex/national", wantStatus: http.StatusOK},
+		{path: "/api/v2/berry/cheri", wantStatus: http.StatusNotFound},
+		{path: "/pokemon/pikachu", wantStatus: http.StatusNotFound},
+	}
+	for _, c := range cases {
+		resp, err := http.Get(server.URL + c.path)
+		if err != nil {
+			t.Fatalf("unexpected error getting %s: %v", c.path, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != c.wantStatus {
+			t.Errorf("%s: expected status %d, got %d", c.path, c.wantStatus, resp.StatusCode)
+		}
+	}
+}
+
+func TestListPaging(t *testing.T) {
+	server, err := NewTestServer()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer server.Close()
+
+	var names []string
+	url := server.URL + "/api/v2/location-area?limit=2"
+	for pages := 0; url != ""; pages++ {
+		if pages > 10 {
+			t.Fatalf("too many pages")
+		}
+		resp, err := http.Get(url)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var page listPage
+		err = json.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatalf("unexpected error decoding page: %v", err)
+		}
+		if pages > 0 && page.Previous == nil {
+			t.Errorf("expected previous link on page %d", pages)
+		}
+		for _, result := range page.Results {
+			names = append(names, result.Name)
+		}
+		url = ""
+		if page.Next != nil {
+			url = *page.Next
+		}
+	}
+
+	want := []string{"canalave-city-area", "pastoria-city-area", "eterna-forest-area"}
+	if len(names) != len(want) {
+		t.Fatalf("expected %v, got %v", want, names)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Errorf("expected %v, got %v", want, names)
+			break
+		}
+	}
+}
@@ -0,0 +1,547 @@
+// Package lineedit is a small readline-style line editor: cursor movement,
+// history with a persistent file, reverse search and tab completion.
+package lineedit
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"golang.org/x/term"
+)
+
+// ErrInterrupted is returned by ReadLine when the user presses Ctrl-C.
+var ErrInterrupted = errors.New("interrupted")
+
+const defaultMaxHistory = 1000
+
+// A Completer returns candidates for the word ending at the cursor, given
+// the text of the line up to the cursor. Candidates are whole words; the
+// editor works out what to insert.
+type Completer func(head string) []string
+
+type Editor struct {
+	in  *bufio.Reader
+	out io.Writer
+	// fd is the terminal to switch into raw mode, or -1 when input isn't a
+	// terminal, in which case lines are read without any editing.
+	fd          int
+	editing     bool
+	history     []string
+	historyFile string
+	maxHistory  int
+	completer   Completer
+	// interrupt, if set, makes a plain read give up with ErrInterrupted.
+	// The line it was waiting for is still delivered to the next read, via
+	// pending.
+	interrupt <-chan os.Signal
+	pending   chan plainResult
+}
+
+type plainResult struct {
+	line string
+	err  error
+}
+
+// New returns an editor reading from in. Editing is only enabled when in is
+// a terminal; otherwise ReadLine just reads lines.
+func New(in *os.File, out io.Writer) *Editor {
+	e := newEditor(in, out, term.IsTerminal(int(in.Fd())))
+	if e.editing {
+		e.fd = int(in.Fd())
+	}
+	return e
+}
+
+func newEditor(in io.Reader, out io.Writer, editing bool) *Editor {
+	return &Editor{
+		in:         bufio.NewReader(in),
+		out:        out,
+		fd:         -1,
+		editing:    editing,
+		maxHistory: defaultMaxHistory,
+	}
+}
+
+// Interactive reports whether the editor is reading from a terminal.
+func (e *Editor) Interactive() bool {
+	return e.editing
+}
+
+func (e *Editor) SetCompleter(c Completer) {
+	e.completer = c
+}
+
+// SetInterrupt makes ReadLine return ErrInterrupted when a value arrives on
+// c while it's waiting for input that isn't from a terminal. In a terminal,
+// Ctrl-C is read as a key instead.
+func (e *Editor) SetInterrupt(c <-chan os.Signal) {
+	e.interrupt = c
+}
+
+// SetHistoryFile loads history from path, if it exists, and appends each
+// line read from now on to it.
+func (e *Editor) SetHistoryFile(path string) error {
+	e.historyFile = path
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("can't read history file: %w", err)
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if line != "" {
+			e.addHistory(line)
+		}
+	}
+	return nil
+}
+
+func (e *Editor) History() []string {
+	return e.history
+}
+
+func (e *Editor) addHistory(line string) {
+	if len(e.history) > 0 && e.history[len(e.history)-1] == line {
+		return
+	}
+	e.history = append(e.history, line)
+	if len(e.history) > e.maxHistory {
+		e.history = e.history[len(e.history)-e.maxHistory:]
+	}
+}
+
+func (e *Editor) saveHistory(line string) error {
+	if e.historyFile == "" {
+		return nil
+	}
+	err := os.MkdirAll(filepath.Dir(e.historyFile), 0o755)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(e.historyFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(f, line)
+	if err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// ReadLine prints prompt and reads a line, adding it to history. It returns
+// io.EOF at the end of input or on Ctrl-D at an empty line, and
+// ErrInterrupted on Ctrl-C, or on a signal set with SetInterrupt.
+func (e *Editor) ReadLine(prompt string) (string, error) {
+	line, err := e.read(prompt)
+	if err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(line) != "" {
+		e.addHistory(line)
+		// Losing history is not worth failing the command over.
+		_ = e.saveHistory(line)
+	}
+	return line, nil
+}
+
+// Ask is ReadLine for answers to questions, which don't belong in history.
+func (e *Editor) Ask(prompt string) (string, error) {
+	return e.read(prompt)
+}
+
+func (e *Editor) read(prompt string) (string, error) {
+	var line string
+	var err error
+	if e.editing {
+		line, err = e.readEdited(prompt)
+	} else {
+		line, err = e.readPlain(prompt)
+	}
+	return line, err
+}
+
+func (e *Editor) readPlain(prompt string) (string, error) {
+	fmt.Fprint(e.out, prompt)
+	if e.interrupt == nil && e.pending == nil {
+		return e.readPlainLine()
+	}
+	// Read in the background so a signal can end the wait. An interrupted
+	// read carries on, and the next call picks up its line.
+	if e.pending == nil {
+		e.pending = make(chan plainResult, 1)
+		go func(pending chan<- plainResult) {
+			line, err := e.readPlainLine()
+			pending <- plainResult{line: line, err: err}
+		}(e.pending)
+	}
+	select {
+	case r := <-e.pending:
+		e.pending = nil
+		return r.line, r.err
+	case <-e.interrupt:
+		return "", ErrInterrupted
+	}
+}
+
+func (e *Editor) readPlainLine() (string, error) {
+	line, err := e.in.ReadString('\n')
+	if err == io.EOF && line != "" {
+		err = nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func (e *Editor) readEdited(prompt string) (string, error) {
+	if e.fd >= 0 {
+		state, err := term.MakeRaw(e.fd)
+		if err != nil {
+			return "", fmt.Errorf("can't put terminal in raw mode: %w", err)
+		}
+		defer term.Restore(e.fd, state)
+	}
+	l := &lineState{editor: e, prompt: prompt, historyPos: len(e.history)}
+	l.refresh()
+	for {
+		r, _, err := e.in.ReadRune()
+		if err != nil {
+			return "", err
+		}
+		done, err := l.handle(r)
+		if done || err != nil {
+			return string(l.buf), err
+		}
+	}
+}
+
+// Control keys.
+const (
+	keyCtrlA     = 1
+	keyCtrlB     = 2
+	keyCtrlC     = 3
+	keyCtrlD     = 4
+	keyCtrlE     = 5
+	keyCtrlF     = 6
+	keyCtrlG     = 7
+	keyCtrlH     = 8
+	keyTab       = 9
+	keyCtrlJ     = 10
+	keyCtrlK     = 11
+	keyCtrlL     = 12
+	keyEnter     = 13
+	keyCtrlN     = 14
+	keyCtrlP     = 16
+	keyCtrlR     = 18
+	keyCtrlU     = 21
+	keyCtrlW     = 23
+	keyEscape    = 27
+	keyBackspace = 127
+)
+
+// lineState is the line being edited by one ReadLine call.
+type lineState struct {
+	editor *Editor
+	prompt string
+	buf    []rune
+	pos    int
+	// historyPos indexes editor.history; len(history) means the line being
+	// typed, which is stashed in pending while browsing.
+	historyPos int
+	pending    []rune
+	lastTab    bool
+	search     *searchState
+}
+
+func (l *lineState) write(s string) {
+	fmt.Fprint(l.editor.out, s)
+}
+
+// refresh redraws the prompt and line and puts the cursor back in place.
+func (l *lineState) refresh() {
+	if l.search != nil {
+		l.search.refresh(l)
+		return
+	}
+	l.write("\r" + l.prompt + string(l.buf) + "\x1b[K")
+	if back := len(l.buf) - l.pos; back > 0 {
+		l.write(fmt.Sprintf("\x1b[%dD", back))
+	}
+}
+
+func (l *lineState) set(buf []rune) {
+	l.buf = append([]rune(nil), buf...)
+	l.pos = len(l.buf)
+}
+
+func (l *lineState) insert(r rune) {
+	l.buf = append(l.buf[:l.pos], append([]rune{r}, l.buf[l.pos:]...)...)
+	l.pos++
+}
+
+// handle applies one key press and reports whether the line is finished.
+func (l *lineState) handle(r rune) (bool, error) {
+	if l.search != nil {
+		return l.search.handle(l, r)
+	}
+	tab := r == keyTab
+	defer func() { l.lastTab = tab }()
+
+	switch r {
+	case keyEnter, keyCtrlJ:
+		l.write("\r\n")
+		return true, nil
+	case keyCtrlC:
+		l.write("^C\r\n")
+		return true, ErrInterrupted
+	case keyCtrlD:
+		if len(l.buf) == 0 {
+			l.write("\r\n")
+			return true, io.EOF
+		}
+		l.deleteForward()
+	case keyBackspace, keyCtrlH:
+		if l.pos > 0 {
+			l.buf = append(l.buf[:l.pos-1], l.buf[l.pos:]...)
+			l.pos--
+		}
+	case keyCtrlA:
+		l.pos = 0
+	case keyCtrlE:
+		l.pos = len(l.buf)
+	case keyCtrlB:
+		l.left()
+	case keyCtrlF:
+		l.right()
+	case keyCtrlK:
+		l.buf = l.buf[:l.pos]
+	case keyCtrlU:
+		l.buf = l.buf[l.pos:]
+		l.pos = 0
+	case keyCtrlW:
+		l.deleteWord()
+	case keyCtrlL:
+		l.write("\x1b[H\x1b[2J")
+	case keyCtrlP:
+		l.historyMove(-1)
+	case keyCtrlN:
+		l.historyMove(1)
+	case keyCtrlR:
+		l.search = &searchState{original: l.buf, index: len(l.editor.history)}
+	case keyTab:
+		l.complete()
+	case keyEscape:
+		l.escape()
+	default:
+		if unicode.IsPrint(r) {
+			l.insert(r)
+		}
+	}
+	l.refresh()
+	return false, nil
+}
+
+func (l *lineState) left() {
+	if l.pos > 0 {
+		l.pos--
+	}
+}
+
+func (l *lineState) right() {
+	if l.pos < len(l.buf) {
+		l.pos++
+	}
+}
+
+func (l *lineState) deleteForward() {
+	if l.pos < len(l.buf) {
+		l.buf = append(l.buf[:l.pos], l.buf[l.pos+1:]...)
+	}
+}
+
+func (l *lineState) deleteWord() {
+	start := l.pos
+	for start > 0 && l.buf[start-1] == ' ' {
+		start--
+	}
+	for start > 0 && l.buf[start-1] != ' ' {
+		start--
+	}
+	l.buf = append(l.buf[:start], l.buf[l.pos:]...)
+	l.pos = start
+}
+
+func (l *lineState) historyMove(delta int) {
+	history := l.editor.history
+	next := l.historyPos + delta
+	if next < 0 || next > len(history) {
+		return
+	}
+	if l.historyPos == len(history) {
+		l.pending = l.buf
+	}
+	l.historyPos = next
+	if next == len(history) {
+		l.set(l.pending)
+		return
+	}
+	l.set([]rune(history[next]))
+}
+
+// escape handles the ANSI sequences sent by arrow, Home, End and Delete.
+func (l *lineState) escape() {
+	in := l.editor.in
+	r, _, err := in.ReadRune()
+	if err != nil || (r != '[' && r != 'O') {
+		return
+	}
+	r, _, err = in.ReadRune()
+	if err != nil {
+		return
+	}
+	switch r {
+	case 'A':
+		l.historyMove(-1)
+	case 'B':
+		l.historyMove(1)
+	case 'C':
+		l.right()
+	case 'D':
+		l.left()
+	case 'H':
+		l.pos = 0
+	case 'F':
+		l.pos = len(l.buf)
+	case '1', '3', '4', '7', '8':
+		// VT style keys end in '~', e.g. ESC [ 3 ~ for Delete.
+		if next, _, err := in.ReadRune(); err != nil || next != '~' {
+			return
+		}
+		switch r {
+		case '1', '7':
+			l.pos = 0
+		case '4', '8':
+			l.pos = len(l.buf)
+		case '3':
+			l.deleteForward()
+		}
+	}
+}
+
+// complete inserts the longest prefix shared by every candidate for the
+// word at the cursor. When that doesn't narrow things down, a second Tab
+// lists the candidates.
+func (l *lineState) complete() {
+	if l.editor.completer == nil {
+		return
+	}
+	head := string(l.buf[:l.pos])
+	start := strings.LastIndex(head, " ") + 1
+	word := head[start:]
+	var candidates []string
+	for _, c := range l.editor.completer(head) {
+		if strings.HasPrefix(c, word) {
+			candidates = append(candidates, c)
+		}
+	}
+	if len(candidates) == 0 {
+		return
+	}
+	prefix := commonPrefix(candidates)
+	if len(candidates) == 1 {
+		prefix += " "
+	}
+	if prefix != word {
+		for _, r := range prefix[len(word):] {
+			l.insert(r)
+		}
+		return
+	}
+	if l.lastTab {
+		l.write("\r\n" + strings.Join(candidates, "  ") + "\r\n")
+	}
+}
+
+func commonPrefix(words []string) string {
+	prefix := words[0]
+	for _, w := range words[1:] {
+		for !strings.HasPrefix(w, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return prefix
+}
+
+// searchState is an in-progress Ctrl-R reverse history search.
+type searchState struct {
+	query    []rune
+	original []rune
+	// index is the history entry currently matched; len(history) means no
+	// match yet.
+	index  int
+	failed bool
+}
+
+func (s *searchState) refresh(l *lineState) {
+	label := "reverse-i-search"
+	if s.failed {
+		label = "failing reverse-i-search"
+	}
+	l.write(fmt.Sprintf("\r(%s)`%s': %s\x1b[K", label, string(s.query), string(l.buf)))
+}
+
+// find looks for the query in history entries older than from.
+func (s *searchState) find(l *lineState, from int) {
+	history := l.editor.history
+	for i := min(from, len(history)) - 1; i >= 0; i-- {
+		if strings.Contains(history[i], string(s.query)) {
+			s.index = i
+			s.failed = false
+			l.set([]rune(history[i]))
+			return
+		}
+	}
+	s.failed = true
+}
+
+func (s *searchState) handle(l *lineState, r rune) (bool, error) {
+	switch r {
+	case keyCtrlR:
+		s.find(l, s.index)
+	case keyBackspace, keyCtrlH:
+		if len(s.query) > 0 {
+			s.query = s.query[:len(s.query)-1]
+			s.find(l, len(l.editor.history))
+		}
+	case keyCtrlG, keyCtrlC:
+		l.search = nil
+		l.set(s.original)
+	case keyEnter, keyCtrlJ:
+		l.search = nil
+		l.refresh()
+		l.write("\r\n")
+		return true, nil
+	default:
+		if unicode.IsPrint(r) {
+			s.query = append(s.query, r)
+			s.find(l, s.index+1)
+			break
+		}
+		// Any other key ends the search, keeping the match to edit, and
+		// then does its usual job.
+		l.search = nil
+		return l.handle(r)
+	}
+	l.refresh()
+	return false, nil
+}
@@ -0,0 +1,147 @@
+package lineedit
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadLineEditing(t *testing.T) {
+	cases := []struct {
+		name    string
+		history []string
+		input   string
+		want    string
+	}{
+		{name: "plain", input: "map\r", want: "map"},
+		{name: "backspace", input: "mapp\x7f\r", want: "map"},
+		{name: "left arrow insert", input: "mp\x1b[Da\r", want: "map"},
+		{name: "home and end", input: "ap\x01m\x05b\r", want: "mapb"},
+		{name: "delete key", input: "maxp\x1b[D\x1b[D\x1b[3~\r", want: "map"},
+		{name: "kill to end", input: "map foo\x1b[D\x1b[D\x1b[D\x0b\x7f\r", want: "map"},
+		{name: "delete word", input: "catch pikachu\x17ditto\r", want: "catch ditto"},
+		{name: "history up", history: []string{"map", "explore a"}, input: "\x1b[A\x1b[A\r", want: "map"},
+		{name: "history up and down", history: []string{"map"}, input: "ex\x1b[A\x1b[B\r", want: "ex"},
+		{name: "reverse search", history: []string{"catch ditto", "map", "explore a"}, input: "\x12cat\r", want: "catch ditto"},
+		{name: "reverse search older match", history: []string{"map 1", "map 2"}, input: "\x12map\x12\r", want: "map 1"},
+		{name: "reverse search then edit", history: []string{"catch ditto"}, input: "\x12dit\x05 now\r", want: "catch ditto now"},
+		{name: "reverse search cancel", history: []string{"catch ditto"}, input: "ex\x12dit\x07\r", want: "ex"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			e := newEditor(strings.NewReader(c.input), io.Discard, true)
+			for _, h := range c.history {
+				e.addHistory(h)
+			}
+			got, err := e.ReadLine("> ")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != c.want {
+				t.Errorf("expected %q, got %q", c.want, got)
+			}
+		})
+	}
+}
+
+func TestReadLineControl(t *testing.T) {
+	cases := []struct {
+		name  string
+		input string
+		want  error
+	}{
+		{name: "ctrl-d on empty line", input: "\x04", want: io.EOF},
+		{name: "ctrl-c", input: "map\x03", want: ErrInterrupted},
+		{name: "end of input", input: "map", want: io.EOF},
+	}
+	for _, c := range cases {
+		e := newEditor(strings.NewReader(c.input), io.Discard, true)
+		_, err := e.ReadLine("> ")
+		if !errors.Is(err, c.want) {
+			t.Errorf("%s: expected %v, got %v", c.name, c.want, err)
+		}
+	}
+}
+
+func TestReadLineInterruptPlain(t *testing.T) {
+	r, w := io.Pipe()
+	defer w.Close()
+	e := newEditor(r, io.Discard, false)
+	interrupt := make(chan os.Signal, 1)
+	e.SetInterrupt(interrupt)
+
+	// Nothing has been written yet, so only the interrupt can end the read.
+	interrupt <- os.Interrupt
+	_, err := e.ReadLine("")
+	if !errors.Is(err, ErrInterrupted) {
+		t.Fatalf("expected ErrInterrupted, got %v", err)
+	}
+	// The abandoned read still delivers the next line.
+	go w.Write([]byte("map\n"))
+	got, err := e.ReadLine("")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "map" {
+		t.Errorf("expected %q, got %q", "map", got)
+	}
+}
+
+func TestTabCompletion(t *testing.T) {
+	completer := func(head string) []string {
+		if strings.HasPrefix(head, "catch ") {
+			return []string{"pikachu", "pichu", "magikarp"}
+		}
+		return []string{"catch", "map", "mapb"}
+	}
+	cases := []struct {
+		input string
+		want  string
+	}{
+		{input: "ca\t\r", want: "catch "},
+		{input: "catch mag\t\r", want: "catch magikarp "},
+		{input: "catch pi\t\r", want: "catch pi"},
+		{input: "catch pik\t\r", want: "catch pikachu "},
+		{input: "ma\t\r", want: "map"},
+		{input: "zz\t\r", want: "zz"},
+	}
+	for _, c := range cases {
+		e := newEditor(strings.NewReader(c.input), io.Discard, true)
+		e.SetCompleter(completer)
+		got, err := e.ReadLine("> ")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != c.want {
+			t.Errorf("%q: expected %q, got %q", c.input, c.want, got)
+		}
+	}
+}
+
+func TestHistoryFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history")
+	e := newEditor(strings.NewReader("map\nexplore a\n\n"), io.Discard, false)
+	err := e.SetHistoryFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for range 3 {
+		_, err := e.ReadLine("> ")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	reloaded := newEditor(strings.NewReader(""), io.Discard, false)
+	err = reloaded.SetHistoryFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := reloaded.History()
+	if len(got) != 2 || got[0] != "map" || got[1] != "explore a" {
+		t.Errorf("expected [map explore a], got %v", got)
+	}
+}
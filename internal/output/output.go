@@ -0,0 +1,70 @@
+// Package output prints command results as text for people or as JSON or
+// YAML for tools.
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+type Format string
+
+const (
+	Text Format = "text"
+	JSON Format = "json"
+	YAML Format = "yaml"
+)
+
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(s); f {
+	case Text, JSON, YAML:
+		return f, nil
+	}
+	return "", fmt.Errorf("unknown output format '%s', expected text, json or yaml", s)
+}
+
+// A Result is anything a command prints. Structured formats encode it by
+// its json struct tags, so those names are the stable interface for tools.
+type Result interface {
+	WriteText(w io.Writer) error
+}
+
+type Printer struct {
+	w      io.Writer
+	format Format
+}
+
+func NewPrinter(w io.Writer, format Format) *Printer {
+	return &Printer{w: w, format: format}
+}
+
+func (p *Printer) Format() Format {
+	return p.format
+}
+
+func (p *Printer) SetFormat(format Format) {
+	p.format = format
+}
+
+// Structured reports whether output is meant for tools rather than people.
+func (p *Printer) Structured() bool {
+	return p.format != Text
+}
+
+// Print writes r in the current format: JSON as one object per line, YAML
+// as one document per result.
+func (p *Printer) Print(r Result) error {
+	switch p.format {
+	case JSON:
+		return json.NewEncoder(p.w).Encode(r)
+	case YAML:
+		data, err := marshalYAML(r)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(p.w, "---\n%s", data)
+		return err
+	}
+	return r.WriteText(p.w)
+}
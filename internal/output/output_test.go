@@ -0,0 +1,105 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"testing"
+)
+
+type stat struct {
+	Name     string `json:"name"`
+	BaseStat int    `json:"base_stat"`
+}
+
+type testResult struct {
+	Name   string   `json:"name"`
+	Caught bool     `json:"caught"`
+	Types  []string `json:"types"`
+	Stats  []stat   `json:"stats"`
+	Note   string   `json:"note,omitempty"`
+}
+
+func (r testResult) WriteText(w io.Writer) error {
+	_, err := fmt.Fprintf(w, "Name: %s\n", r.Name)
+	return err
+}
+
+func TestPrint(t *testing.T) {
+	r := testResult{
+		Name:   "pikachu",
+		Caught: true,
+		Types:  []string{"electric"},
+		Stats:  []stat{{Name: "hp", BaseStat: 35}, {Name: "speed", BaseStat: 90}},
+	}
+	cases := []struct {
+		format Format
+		want   string
+	}{
+		{format: Text, want: "Name: pikachu\n"},
+		{
+			format: JSON,
+			want:   `{"name":"pikachu","caught":true,"types":["electric"],"stats":[{"name":"hp","base_stat":35},{"name":"speed","base_stat":90}]}` + "\n",
+		},
+		{
+			format: YAML,
+			want: `---
+name: pikachu
+caught: true
+types:
+  - electric
+stats:
+  - name: hp
+    base_stat: 35
+  - name: speed
+    base_stat: 90
+`,
+		},
+	}
+	for _, c := range cases {
+		var buf bytes.Buffer
+		err := NewPrinter(&buf, c.format).Print(r)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", c.format, err)
+		}
+		if buf.String() != c.want {
+			t.Errorf("%s: expected\n%s\ngot\n%s", c.format, c.want, buf.String())
+		}
+	}
+}
+
+func TestYAMLScalars(t *testing.T) {
+	cases := []struct {
+		value any
+		want  string
+	}{
+		{value: map[string]any{"s": "mt-coronet-2f"}, want: "s: mt-coronet-2f\n"},
+		{value: map[string]any{"s": ""}, want: "s: \"\"\n"},
+		{value: map[string]any{"s": "yes"}, want: "s: \"yes\"\n"},
+		{value: map[string]any{"s": "123"}, want: "s: \"123\"\n"},
+		{value: map[string]any{"s": "a: b"}, want: "s: \"a: b\"\n"},
+		{value: map[string]any{"s": nil}, want: "s: null\n"},
+		{value: map[string]any{"l": []string{}}, want: "l: []\n"},
+		{value: map[string]any{"m": map[string]any{}}, want: "m: {}\n"},
+	}
+	for _, c := range cases {
+		got, err := marshalYAML(c.value)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if string(got) != c.want {
+			t.Errorf("expected %q, got %q", c.want, got)
+		}
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for _, s := range []string{"text", "json", "yaml"} {
+		if _, err := ParseFormat(s); err != nil {
+			t.Errorf("%s: unexpected error: %v", s, err)
+		}
+	}
+	if _, err := ParseFormat("xml"); err == nil {
+		t.Errorf("expected error for unknown format")
+	}
+}
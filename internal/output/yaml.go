@@ -0,0 +1,154 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// marshalYAML encodes v as block-style YAML. It goes through encoding/json
+// so field names and omitempty behave exactly as they do for JSON output,
+// and keeps fields in struct order.
+func marshalYAML(v any) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	node, err := decodeNode(dec)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	writeNode(&buf, node, 0, false)
+	return buf.Bytes(), nil
+}
+
+type field struct {
+	key   string
+	value any
+}
+
+// object keeps JSON object fields in their original order, which a map
+// would lose.
+type object []field
+
+func decodeNode(dec *json.Decoder) (any, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch tok {
+	case json.Delim('{'):
+		var obj object
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeNode(dec)
+			if err != nil {
+				return nil, err
+			}
+			obj = append(obj, field{key: key.(string), value: value})
+		}
+		_, err = dec.Token()
+		return obj, err
+	case json.Delim('['):
+		list := []any{}
+		for dec.More() {
+			value, err := decodeNode(dec)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, value)
+		}
+		_, err = dec.Token()
+		return list, err
+	}
+	return tok, nil
+}
+
+func writeNode(buf *bytes.Buffer, node any, indent int, inList bool) {
+	pad := strings.Repeat("  ", indent)
+	switch n := node.(type) {
+	case object:
+		if len(n) == 0 {
+			buf.WriteString("{}\n")
+			return
+		}
+		for i, f := range n {
+			// The first field of a list item shares the "- " line.
+			if i > 0 || !inList {
+				buf.WriteString(pad)
+			}
+			buf.WriteString(scalar(f.key) + ":")
+			writeChild(buf, f.value, indent)
+		}
+	case []any:
+		if len(n) == 0 {
+			buf.WriteString("[]\n")
+			return
+		}
+		for i, item := range n {
+			if i > 0 || !inList {
+				buf.WriteString(pad)
+			}
+			buf.WriteString("- ")
+			writeNode(buf, item, indent+1, true)
+		}
+	default:
+		buf.WriteString(scalar(n) + "\n")
+	}
+}
+
+// writeChild writes the value of a mapping key: scalars and empty
+// collections inline, everything else on the following lines.
+func writeChild(buf *bytes.Buffer, value any, indent int) {
+	if isEmptyOrScalar(value) {
+		buf.WriteString(" ")
+		writeNode(buf, value, indent+1, false)
+		return
+	}
+	buf.WriteString("\n")
+	writeNode(buf, value, indent+1, false)
+}
+
+func isEmptyOrScalar(value any) bool {
+	switch v := value.(type) {
+	case object:
+		return len(v) == 0
+	case []any:
+		return len(v) == 0
+	}
+	return true
+}
+
+var plainString = regexp.MustCompile(`^[A-Za-z_./][A-Za-z0-9 _./()'!?,-]*$`)
+
+var reservedWords = map[string]bool{
+	"true": true, "false": true, "yes": true, "no": true, "on": true,
+	"off": true, "null": true, "y": true, "n": true, "~": true,
+}
+
+func scalar(v any) string {
+	switch s := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return fmt.Sprint(s)
+	case json.Number:
+		return s.String()
+	case string:
+		if plainString.MatchString(s) && !strings.HasSuffix(s, " ") && !reservedWords[strings.ToLower(s)] {
+			return s
+		}
+		// A JSON string is also a valid YAML double-quoted string.
+		quoted, _ := json.Marshal(s)
+		return string(quoted)
+	}
+	return fmt.Sprint(v)
+}
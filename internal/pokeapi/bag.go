@@ -0,0 +1,109 @@
+package pokeapi
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// A Bag counts the items you carry, keyed by PokeAPI item name.
+type Bag map[string]int
+
+// Balls lists every ball you can throw, best last. A Master Ball's bonus is
+// big enough that it can't fail.
+var Balls = []Ball{
+	PokeBall,
+	{Name: "great-ball", Bonus: 1.5},
+	{Name: "ultra-ball", Bonus: 2},
+	{Name: "master-ball", Bonus: 255},
+}
+
+func BallByName(name string) (Ball, bool) {
+	i := slices.IndexFunc(Balls, func(b Ball) bool { return b.Name == name })
+	if i < 0 {
+		return Ball{}, false
+	}
+	return Balls[i], true
+}
+
+// startingBag is what a new trainer, or one from before the bag existed,
+// sets out with.
+func startingBag() Bag {
+	return Bag{
+		"poke-ball":  20,
+		"great-ball": 5,
+		"ultra-ball": 2,
+	}
+}
+
+// Bag returns what you're carrying, leaving out anything you've run out of.
+func (c *Client) Bag() Bag {
+	bag := make(Bag)
+	for name, count := range c.config.Bag {
+		if count > 0 {
+			bag[name] = count
+		}
+	}
+	return bag
+}
+
+// Item is the part of PokeAPI's /item resource the bag shows.
+type Item struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	Cost     int    `json:"cost"`
+	Category struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	} `json:"category"`
+	EffectEntries []struct {
+		Effect      string `json:"effect"`
+		ShortEffect string `json:"short_effect"`
+		Language    struct {
+			Name string `json:"name"`
+			URL  string `json:"url"`
+		} `json:"language"`
+	} `json:"effect_entries"`
+	Names []struct {
+		Name     string `json:"name"`
+		Language struct {
+			Name string `json:"name"`
+			URL  string `json:"url"`
+		} `json:"language"`
+	} `json:"names"`
+}
+
+// DisplayName is the English name, e.g. "Great Ball" for great-ball.
+func (i Item) DisplayName() string {
+	for _, name := range i.Names {
+		if name.Language.Name == "en" {
+			return name.Name
+		}
+	}
+	return i.Name
+}
+
+// Description is the English short effect, flattened to one line.
+func (i Item) Description() string {
+	for _, entry := range i.EffectEntries {
+		if entry.Language.Name == "en" {
+			return strings.Join(strings.Fields(entry.ShortEffect), " ")
+		}
+	}
+	return ""
+}
+
+func (c *Client) GetItem(name string) (Item, error) {
+	return c.GetItemContext(context.Background(), name)
+}
+
+func (c *Client) GetItemContext(ctx context.Context, name string) (Item, error) {
+	var item Item
+	url := fmt.Sprintf("%s/item/%s", c.apiUrl, name)
+	err := c.getJSON(ctx, url, &item)
+	if err != nil {
+		return Item{}, notFoundAs(err, "item", name)
+	}
+	return item, nil
+}
@@ -0,0 +1,43 @@
+package pokeapi
+
+import (
+	"testing"
+)
+
+func TestGetItem(t *testing.T) {
+	c := newFixtureClient(t)
+	cases := []struct {
+		name            string
+		wantName        string
+		wantDescription string
+	}{
+		{"poke-ball", "Poké Ball", "Tries to catch a wild Pokémon."},
+		{"great-ball", "Great Ball", "Tries to catch a wild Pokémon. Success rate is 1.5×."},
+		{"master-ball", "Master Ball", "Catches a wild Pokémon every time."},
+	}
+	for _, tc := range cases {
+		item, err := c.GetItem(tc.name)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		}
+		if item.DisplayName() != tc.wantName {
+			t.Errorf("%s: expected name %q, got %q", tc.name, tc.wantName, item.DisplayName())
+		}
+		if item.Description() != tc.wantDescription {
+			t.Errorf("%s: expected description %q, got %q", tc.name, tc.wantDescription, item.Description())
+		}
+	}
+}
+
+func TestStartingBag(t *testing.T) {
+	c := newFixtureClient(t)
+	bag := c.Bag()
+	if bag["poke-ball"] == 0 {
+		t.Errorf("expected a new trainer to have poke balls, got %v", bag)
+	}
+	for name := range bag {
+		if _, ok := BallByName(name); !ok {
+			t.Errorf("expected only balls in the starting bag, got %s", name)
+		}
+	}
+}
@@ -0,0 +1,446 @@
+package pokeapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// A Battler is one side of a battle, with stats worked out for its level.
+// Wild Pokemon and yours alike have no IVs or EVs.
+type Battler struct {
+	Name      string
+	Species   string
+	OwnedID   int // 0 for the wild Pokemon
+	Level     int
+	MaxHP     int
+	HP        int
+	Attack    int
+	Defense   int
+	SpAttack  int
+	SpDefense int
+	Speed     int
+	Types     []string
+	Moves     []Move
+	Status    Status
+	// moveTypes is the type chart row for each move's type, by move name,
+	// fetched once when the battler is built.
+	moveTypes map[string]Type
+}
+
+func (b *Battler) Fainted() bool {
+	return b.HP <= 0
+}
+
+type BattleOutcome string
+
+const (
+	BattleOngoing BattleOutcome = ""
+	BattleWon     BattleOutcome = "won"
+	BattleLost    BattleOutcome = "lost"
+)
+
+// A BattleEvent is one attack. Effectiveness multiplies the damage by how
+// well the move's type does against the target; 1 is neutral. Status is
+// the condition the attack left the target with, if it caused one.
+type BattleEvent struct {
+	Attacker      string
+	Target        string
+	Move          string
+	Missed        bool
+	Damage        int
+	Effectiveness float64
+	TargetHP      int
+	TargetMaxHP   int
+	Fainted       bool
+	Status        Status
+}
+
+type TurnResult struct {
+	Events  []BattleEvent
+	Outcome BattleOutcome
+}
+
+type BattleStatus struct {
+	Wild   Battler
+	Active Battler
+}
+
+type battle struct {
+	wild   *Battler
+	active *Battler
+	// party holds everyone sent out so far, so HP carries over when you
+	// switch back.
+	party map[int]*Battler
+}
+
+var (
+	ErrNoBattle = errors.New("not in a battle")
+	ErrInBattle = errors.New("already in a battle")
+)
+
+func (c *Client) StartBattle(ownedID int) (BattleStatus, error) {
+	return c.StartBattleContext(context.Background(), ownedID)
+}
+
+// StartBattleContext sends out the owned Pokemon with ownedID against the
+// wild Pokemon. An ownedID of 0 sends out the first Pokemon in the box.
+func (c *Client) StartBattleContext(ctx context.Context, ownedID int) (BattleStatus, error) {
+	if c.battle != nil {
+		return BattleStatus{}, ErrInBattle
+	}
+	if c.wild == nil {
+		return BattleStatus{}, ErrNoWild
+	}
+	if ownedID == 0 {
+		if len(c.config.Box) == 0 {
+			return BattleStatus{}, errors.New("you have no pokemon to fight with, catch one first")
+		}
+		ownedID = c.config.Box[0].ID
+	}
+	wildPokemon, err := c.GetPokemonContext(ctx, c.wild.Pokemon)
+	if err != nil {
+		return BattleStatus{}, err
+	}
+	wild, err := c.newBattler(ctx, wildPokemon, c.wild.Level)
+	if err != nil {
+		return BattleStatus{}, err
+	}
+	// A Pokemon worn down before, e.g. by a failed catch, stays worn down.
+	if c.wild.MaxHP > 0 {
+		wild.HP = min(c.wild.HP, wild.MaxHP)
+	}
+	wild.Status = c.wild.Status
+	c.wild.MaxHP, c.wild.HP = wild.MaxHP, wild.HP
+	c.battle = &battle{wild: wild, party: make(map[int]*Battler)}
+	active, err := c.ownedBattler(ctx, ownedID)
+	if err != nil {
+		c.battle = nil
+		return BattleStatus{}, err
+	}
+	c.battle.active = active
+	return c.battleStatus(), nil
+}
+
+// Battle returns how the battle stands, if there is one.
+func (c *Client) Battle() (BattleStatus, bool) {
+	if c.battle == nil {
+		return BattleStatus{}, false
+	}
+	return c.battleStatus(), true
+}
+
+func (c *Client) InBattle() bool {
+	return c.battle != nil
+}
+
+func (c *Client) battleStatus() BattleStatus {
+	return BattleStatus{Wild: *c.battle.wild, Active: *c.battle.active}
+}
+
+func (c *Client) Fight(move string) (TurnResult, error) {
+	return c.FightContext(context.Background(), move)
+}
+
+// FightContext plays one turn: your Pokemon uses move and the wild Pokemon
+// uses one of its own, in order of move priority and then speed.
+func (c *Client) FightContext(ctx context.Context, moveName string) (TurnResult, error) {
+	b := c.battle
+	if b == nil {
+		return TurnResult{}, ErrNoBattle
+	}
+	if b.active.Fainted() {
+		return TurnResult{}, fmt.Errorf("%s has fainted, switch to another pokemon", b.active.Name)
+	}
+	i := slices.IndexFunc(b.active.Moves, func(m Move) bool { return m.Name == moveName })
+	if i < 0 {
+		return TurnResult{}, fmt.Errorf("%s doesn't know %s, try %s", b.active.Name, moveName, strings.Join(moveNames(b.active), ", "))
+	}
+	move := b.active.Moves[i]
+	wildMove, wildCanAttack := c.wildMove()
+
+	type attack struct {
+		attacker, target *Battler
+		move             Move
+	}
+	turn := []attack{{b.active, b.wild, move}}
+	if wildCanAttack {
+		wildAttack := attack{b.wild, b.active, wildMove}
+		if !c.movesFirst(b.active, move, b.wild, wildMove) {
+			turn = []attack{wildAttack, turn[0]}
+		} else {
+			turn = append(turn, wildAttack)
+		}
+	}
+	var result TurnResult
+	for _, a := range turn {
+		if a.attacker.Fainted() {
+			break
+		}
+		result.Events = append(result.Events, c.attack(a.attacker, a.target, a.move))
+	}
+	result.Outcome = c.settleBattle()
+	return result, nil
+}
+
+func moveNames(b *Battler) []string {
+	names := make([]string, len(b.Moves))
+	for i, m := range b.Moves {
+		names[i] = m.Name
+	}
+	return names
+}
+
+// movesFirst decides whether a goes before b. Speed ties are a coin toss.
+func (c *Client) movesFirst(a *Battler, aMove Move, b *Battler, bMove Move) bool {
+	if aMove.Priority != bMove.Priority {
+		return aMove.Priority > bMove.Priority
+	}
+	if a.Speed != b.Speed {
+		return a.Speed > b.Speed
+	}
+	return c.rand.Intn(2) == 0
+}
+
+// wildMove picks what the wild Pokemon does this turn.
+func (c *Client) wildMove() (Move, bool) {
+	moves := c.battle.wild.Moves
+	if len(moves) == 0 {
+		return Move{}, false
+	}
+	return moves[c.rand.Intn(len(moves))], true
+}
+
+// wildTurn lets the wild Pokemon attack on its own, after you switch or
+// throw a ball.
+func (c *Client) wildTurn() TurnResult {
+	var result TurnResult
+	if move, ok := c.wildMove(); ok && !c.battle.active.Fainted() {
+		result.Events = append(result.Events, c.attack(c.battle.wild, c.battle.active, move))
+	}
+	result.Outcome = c.settleBattle()
+	return result
+}
+
+func (c *Client) attack(attacker, target *Battler, move Move) BattleEvent {
+	event := BattleEvent{
+		Attacker:      attacker.Name,
+		Target:        target.Name,
+		Move:          move.Name,
+		Effectiveness: 1,
+		TargetMaxHP:   target.MaxHP,
+	}
+	if move.Accuracy > 0 && c.rand.Intn(100) >= move.Accuracy {
+		event.Missed = true
+		event.TargetHP = target.HP
+		return event
+	}
+	// Type immunities stop status moves too, e.g. thunder wave against a
+	// ground type, but they're never more or less effective than that.
+	event.Effectiveness = effectiveness(attacker.moveTypes[move.Name], target.Types)
+	if move.Power == 0 && event.Effectiveness > 0 {
+		event.Effectiveness = 1
+	}
+	if move.Power > 0 {
+		// Report what was actually lost, not overkill.
+		event.Damage = min(target.HP, damage(attacker, target, move, event.Effectiveness, 85+c.rand.Intn(16)))
+		target.HP -= event.Damage
+	}
+	event.TargetHP = target.HP
+	event.Fainted = target.Fainted()
+	if event.Effectiveness > 0 && !event.Fainted && c.inflicts(move, target) {
+		target.Status = Status(move.Meta.Ailment.Name)
+		event.Status = target.Status
+	}
+	return event
+}
+
+// statusImmunities are the types a status can't affect.
+var statusImmunities = map[Status][]string{
+	StatusParalysis: {"electric"},
+	StatusPoison:    {"poison", "steel"},
+	StatusBurn:      {"fire"},
+	StatusFreeze:    {"ice"},
+}
+
+// inflicts decides whether move gives target a status. Only the ones that
+// help catching are tracked, and a Pokemon can only have one.
+func (c *Client) inflicts(move Move, target *Battler) bool {
+	status := Status(move.Meta.Ailment.Name)
+	if status.catchBonus() == 1 || target.Status != StatusNone {
+		return false
+	}
+	for _, t := range statusImmunities[status] {
+		if slices.Contains(target.Types, t) {
+			return false
+		}
+	}
+	chance := move.Meta.AilmentChance
+	return chance == 0 || c.rand.Intn(100) < chance
+}
+
+// damage is the main-series formula. roll is the random factor, 85 to
+// 100 percent.
+func damage(attacker, target *Battler, move Move, effectiveness float64, roll int) int {
+	if effectiveness == 0 {
+		return 0
+	}
+	attack, defense := attacker.Attack, target.Defense
+	if move.DamageClass.Name == "special" {
+		attack, defense = attacker.SpAttack, target.SpDefense
+	}
+	base := (2*attacker.Level/5+2)*move.Power*attack/max(defense, 1)/50 + 2
+	// Same-type attack bonus.
+	stab := 1.0
+	if slices.Contains(attacker.Types, move.Type.Name) {
+		stab = 1.5
+	}
+	return max(1, int(float64(base)*stab*effectiveness*float64(roll)/100))
+}
+
+// settleBattle copies the wild Pokemon's HP and status back to the
+// encounter, so catching benefits from them, and ends the battle if either side
+// is out of Pokemon.
+func (c *Client) settleBattle() BattleOutcome {
+	b := c.battle
+	c.wild.HP = b.wild.HP
+	c.wild.Status = b.wild.Status
+	if b.wild.Fainted() {
+		c.EndEncounter()
+		return BattleWon
+	}
+	if b.active.Fainted() && !c.canSwitch() {
+		// With nobody left to fight, you flee and the wild Pokemon goes too.
+		c.EndEncounter()
+		return BattleLost
+	}
+	return BattleOngoing
+}
+
+// canSwitch reports whether anyone in the box can still fight.
+func (c *Client) canSwitch() bool {
+	for _, owned := range c.config.Box {
+		b, ok := c.battle.party[owned.ID]
+		if !ok || !b.Fainted() {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Client) Switch(ownedID int) (TurnResult, error) {
+	return c.SwitchContext(context.Background(), ownedID)
+}
+
+// SwitchContext sends out another Pokemon. That takes your turn, unless
+// the one it replaces had fainted.
+func (c *Client) SwitchContext(ctx context.Context, ownedID int) (TurnResult, error) {
+	b := c.battle
+	if b == nil {
+		return TurnResult{}, ErrNoBattle
+	}
+	if ownedID == b.active.OwnedID {
+		return TurnResult{}, fmt.Errorf("%s is already out", b.active.Name)
+	}
+	next, err := c.ownedBattler(ctx, ownedID)
+	if err != nil {
+		return TurnResult{}, err
+	}
+	if next.Fainted() {
+		return TurnResult{}, fmt.Errorf("%s has fainted", next.Name)
+	}
+	free := b.active.Fainted()
+	b.active = next
+	if free {
+		return TurnResult{}, nil
+	}
+	return c.wildTurn(), nil
+}
+
+// Run flees the battle, and the wild Pokemon flees too.
+func (c *Client) Run() error {
+	if c.battle == nil {
+		return ErrNoBattle
+	}
+	c.EndEncounter()
+	return nil
+}
+
+// ownedBattler returns the battler for an owned Pokemon, building it the
+// first time it's sent out.
+func (c *Client) ownedBattler(ctx context.Context, id int) (*Battler, error) {
+	if b, ok := c.battle.party[id]; ok {
+		return b, nil
+	}
+	owned, ok := c.ownedByID(id)
+	if !ok {
+		return nil, fmt.Errorf("you don't own a pokemon #%d", id)
+	}
+	var pokemon Pokemon
+	if entry, ok := c.config.Pokedex[owned.Species]; ok && entry.Pokemon != nil {
+		pokemon = *entry.Pokemon
+	} else {
+		var err error
+		pokemon, err = c.GetPokemonContext(ctx, owned.Species)
+		if err != nil {
+			return nil, err
+		}
+	}
+	b, err := c.newBattler(ctx, pokemon, owned.Level)
+	if err != nil {
+		return nil, err
+	}
+	b.Name = owned.Name()
+	b.OwnedID = owned.ID
+	c.battle.party[id] = b
+	return b, nil
+}
+
+func (c *Client) newBattler(ctx context.Context, p Pokemon, level int) (*Battler, error) {
+	b := &Battler{
+		Name:      p.Name,
+		Species:   p.Name,
+		Level:     level,
+		MaxHP:     hpStat(p, level),
+		moveTypes: make(map[string]Type),
+	}
+	b.HP = b.MaxHP
+	for _, stat := range p.Stats {
+		value := otherStat(stat.BaseStat, level)
+		switch stat.Stat.Name {
+		case "attack":
+			b.Attack = value
+		case "defense":
+			b.Defense = value
+		case "special-attack":
+			b.SpAttack = value
+		case "special-defense":
+			b.SpDefense = value
+		case "speed":
+			b.Speed = value
+		}
+	}
+	for _, t := range p.Types {
+		b.Types = append(b.Types, t.Type.Name)
+	}
+	for _, name := range learnedMoves(p, level) {
+		move, err := c.GetMoveContext(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		t, err := c.GetTypeContext(ctx, move.Type.Name)
+		if err != nil {
+			return nil, err
+		}
+		b.Moves = append(b.Moves, move)
+		b.moveTypes[move.Name] = t
+	}
+	return b, nil
+}
+
+// otherStat is any stat but HP at level, with no IVs, EVs or nature.
+func otherStat(base, level int) int {
+	return 2*base*level/100 + 5
+}
@@ -0,0 +1,316 @@
+package pokeapi
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestLearnedMoves(t *testing.T) {
+	c := newFixtureClient(t)
+	pikachu, err := c.GetPokemon("pikachu")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cases := []struct {
+		level int
+		want  []string
+	}{
+		{1, []string{"thunder-shock", "growl"}},
+		{12, []string{"thunder-shock", "growl", "tail-whip", "thunder-wave"}},
+		// Quick attack pushes out the oldest move; thunderbolt is a TM.
+		{50, []string{"growl", "tail-whip", "thunder-wave", "quick-attack"}},
+	}
+	for _, tc := range cases {
+		got := learnedMoves(pikachu, tc.level)
+		if !slices.Equal(got, tc.want) {
+			t.Errorf("level %d: expected %v, got %v", tc.level, tc.want, got)
+		}
+	}
+}
+
+func TestDamage(t *testing.T) {
+	thunderShock := Move{Name: "thunder-shock", Power: 40}
+	thunderShock.Type.Name = "electric"
+	thunderShock.DamageClass.Name = "special"
+	pikachu := &Battler{Level: 10, SpAttack: 15, Types: []string{"electric"}}
+	magikarp := &Battler{Level: 10, SpDefense: 9, Types: []string{"water"}}
+
+	cases := []struct {
+		name          string
+		effectiveness float64
+		roll          int
+		want          int
+	}{
+		{"best roll", 1, 100, 15},
+		{"worst roll", 1, 85, 12},
+		{"super effective", 2, 100, 30},
+		{"no effect", 0, 100, 0},
+	}
+	for _, tc := range cases {
+		got := damage(pikachu, magikarp, thunderShock, tc.effectiveness, tc.roll)
+		if got != tc.want {
+			t.Errorf("%s: expected %d, got %d", tc.name, tc.want, got)
+		}
+	}
+}
+
+// newBattleClient explores Pastoria City and meets a wild Pokemon there.
+func newBattleClient(t *testing.T, method string, opts ...Option) *Client {
+	t.Helper()
+	c := newFixtureClient(t, opts...)
+	_, err := c.ExploreArea("pastoria-city-area")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = c.Encounter(method, "diamond")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return c
+}
+
+func TestBattleWon(t *testing.T) {
+	c := newBattleClient(t, "old-rod", WithSeed(1))
+	_, err := c.StartBattle(0)
+	if err == nil {
+		t.Errorf("expected error battling with an empty box")
+	}
+	pikachu, err := c.GetPokemon("pikachu")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	c.AddToBox(pikachu, 30, "poke-ball")
+	status, err := c.StartBattle(0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status.Active.Name != "pikachu" || status.Wild.Name != "magikarp" {
+		t.Errorf("expected pikachu against magikarp, got %+v", status)
+	}
+	if _, err := c.StartBattle(0); !errors.Is(err, ErrInBattle) {
+		t.Errorf("expected ErrInBattle, got %v", err)
+	}
+	if _, err := c.Fight("thunderbolt"); err == nil {
+		t.Errorf("expected error using a move pikachu doesn't know")
+	}
+
+	for range 20 {
+		turn, err := c.Fight("quick-attack")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if turn.Outcome == BattleOngoing {
+			wild, _ := c.Wild()
+			battle, _ := c.Battle()
+			if wild.HP != battle.Wild.HP {
+				t.Errorf("expected the encounter to share the battle's HP, got %d and %d", wild.HP, battle.Wild.HP)
+			}
+			continue
+		}
+		if turn.Outcome != BattleWon {
+			t.Fatalf("expected pikachu to win, got %s", turn.Outcome)
+		}
+		if c.InBattle() {
+			t.Errorf("expected the battle to be over")
+		}
+		if _, ok := c.Wild(); ok {
+			t.Errorf("expected the fainted pokemon to be gone")
+		}
+		return
+	}
+	t.Errorf("expected the battle to be over within 20 turns")
+}
+
+func TestBattleEffectiveness(t *testing.T) {
+	c := newBattleClient(t, "old-rod", WithSeed(1))
+	pikachu, err := c.GetPokemon("pikachu")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	c.AddToBox(pikachu, 5, "poke-ball")
+	_, err = c.StartBattle(0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	turn, err := c.Fight("thunder-shock")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, event := range turn.Events {
+		if event.Attacker != "pikachu" || event.Missed {
+			continue
+		}
+		if event.Effectiveness != 2 {
+			t.Errorf("expected thunder shock to be super effective against magikarp, got %v", event.Effectiveness)
+		}
+		return
+	}
+	t.Errorf("expected pikachu to hit with thunder shock, got %+v", turn.Events)
+}
+
+func TestBattleStatus(t *testing.T) {
+	c := newBattleClient(t, "old-rod", WithRand(zeroRand{}))
+	pikachu, err := c.GetPokemon("pikachu")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	c.AddToBox(pikachu, 12, "poke-ball")
+	_, err = c.StartBattle(0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	turn, err := c.Fight("thunder-wave")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(turn.Events) == 0 || turn.Events[0].Status != StatusParalysis {
+		t.Errorf("expected thunder wave to paralyze magikarp, got %+v", turn.Events)
+	}
+	// The encounter keeps the status, so the catch gets its bonus.
+	if wild, _ := c.Wild(); wild.Status != StatusParalysis {
+		t.Errorf("expected the wild pokemon to stay paralyzed, got %q", wild.Status)
+	}
+	battle, _ := c.Battle()
+	if battle.Active.Status != StatusNone {
+		t.Errorf("expected splash not to affect pikachu, got %q", battle.Active.Status)
+	}
+}
+
+func TestStatusMoveImmunity(t *testing.T) {
+	c := newFixtureClient(t, WithRand(zeroRand{}))
+	thunderWave, err := c.GetMove("thunder-wave")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	electric, err := c.GetType("electric")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	pikachu := &Battler{Name: "pikachu", moveTypes: map[string]Type{"thunder-wave": electric}}
+	cases := []struct {
+		types             []string
+		wantEffectiveness float64
+		wantStatus        Status
+	}{
+		{[]string{"water"}, 1, StatusParalysis},
+		// Super effective doesn't mean anything for a status move.
+		{[]string{"water", "flying"}, 1, StatusParalysis},
+		{[]string{"ground"}, 0, StatusNone},
+		{[]string{"electric"}, 1, StatusNone},
+	}
+	for _, tc := range cases {
+		target := &Battler{Name: "target", MaxHP: 20, HP: 20, Types: tc.types}
+		event := c.attack(pikachu, target, thunderWave)
+		if event.Effectiveness != tc.wantEffectiveness || event.Status != tc.wantStatus || target.Status != tc.wantStatus {
+			t.Errorf("%v: expected effectiveness %v and status %q, got %v and %q", tc.types, tc.wantEffectiveness, tc.wantStatus, event.Effectiveness, target.Status)
+		}
+	}
+}
+
+func TestBattleLost(t *testing.T) {
+	// Always rolling 0 finds a level 20 tentacool, which always hits with
+	// poison sting. A level 5 magikarp can only splash.
+	c := newBattleClient(t, "surf", WithRand(zeroRand{}))
+	magikarp, err := c.GetPokemon("magikarp")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	c.AddToBox(magikarp, 5, "poke-ball")
+	_, err = c.StartBattle(0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for range 20 {
+		turn, err := c.Fight("splash")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, event := range turn.Events {
+			if event.Attacker == "magikarp" && event.Damage != 0 {
+				t.Errorf("expected splash to do nothing, did %d", event.Damage)
+			}
+		}
+		if turn.Outcome == BattleLost {
+			if c.InBattle() {
+				t.Errorf("expected the battle to be over")
+			}
+			return
+		}
+		if turn.Outcome != BattleOngoing {
+			t.Fatalf("unexpected outcome %s", turn.Outcome)
+		}
+	}
+	t.Errorf("expected magikarp to lose within 20 turns")
+}
+
+func TestBattleSwitchAndCatch(t *testing.T) {
+	c := newBattleClient(t, "surf", WithRand(zeroRand{}))
+	magikarp, err := c.GetPokemon("magikarp")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	pikachu, err := c.GetPokemon("pikachu")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	first := c.AddToBox(magikarp, 5, "poke-ball")
+	second := c.AddToBox(pikachu, 20, "poke-ball")
+	_, err = c.StartBattle(first.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := c.Switch(first.ID); err == nil {
+		t.Errorf("expected error switching to the pokemon already out")
+	}
+	turn, err := c.Switch(second.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Switching takes your turn, so tentacool attacks pikachu.
+	if len(turn.Events) != 1 || turn.Events[0].Target != "pikachu" {
+		t.Errorf("expected tentacool to attack pikachu, got %+v", turn.Events)
+	}
+
+	_, err = c.Fight("quick-attack")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wild, _ := c.Wild()
+	if wild.HP >= wild.MaxHP {
+		t.Errorf("expected tentacool to be hurt, has %d of %d HP", wild.HP, wild.MaxHP)
+	}
+	// Rolling 0 passes every shake check.
+	result, err := c.CatchWild(PokeBall)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Caught || c.InBattle() {
+		t.Errorf("expected tentacool caught and the battle over")
+	}
+}
+
+func TestRun(t *testing.T) {
+	c := newBattleClient(t, "old-rod")
+	if err := c.Run(); !errors.Is(err, ErrNoBattle) {
+		t.Errorf("expected ErrNoBattle, got %v", err)
+	}
+	c.AddToBox(Pokemon{Name: "ditto"}, 5, "poke-ball")
+	_, err := c.StartBattle(0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := c.Encounter("old-rod", ""); !errors.Is(err, ErrInBattle) {
+		t.Errorf("expected ErrInBattle looking for another pokemon, got %v", err)
+	}
+	err = c.Run()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c.InBattle() {
+		t.Errorf("expected running to end the battle")
+	}
+	if _, ok := c.Wild(); ok {
+		t.Errorf("expected the wild pokemon to be gone")
+	}
+}
@@ -0,0 +1,96 @@
+package pokeapi
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// An OwnedPokemon is one individual you caught. Catching a second pikachu
+// gives you a second OwnedPokemon; the Pokedex only records the species.
+type OwnedPokemon struct {
+	ID       int       `json:"id"`
+	Species  string    `json:"species"`
+	Nickname string    `json:"nickname,omitempty"`
+	Level    int       `json:"level"`
+	CaughtAt time.Time `json:"caught_at"`
+	Location string    `json:"location,omitempty"`
+	Ball     string    `json:"ball"`
+}
+
+// Name is the nickname if there is one, otherwise the species.
+func (o OwnedPokemon) Name() string {
+	if o.Nickname != "" {
+		return o.Nickname
+	}
+	return o.Species
+}
+
+// AddToBox records p as caught in the Pokedex and adds a new individual to
+// the box, caught in the area last explored.
+func (c *Client) AddToBox(p Pokemon, level int, ball string) OwnedPokemon {
+	c.AddPokedexEntry(p)
+	c.config.LastOwnedID++
+	owned := OwnedPokemon{
+		ID:       c.config.LastOwnedID,
+		Species:  p.Name,
+		Level:    level,
+		CaughtAt: time.Now().UTC().Truncate(time.Second),
+		Location: c.config.Area,
+		Ball:     ball,
+	}
+	c.config.Box = append(c.config.Box, owned)
+	return owned
+}
+
+// Box lists every Pokemon you own, in the order they were caught.
+func (c *Client) Box() []OwnedPokemon {
+	return slices.Clone(c.config.Box)
+}
+
+// FindOwned looks up owned Pokemon by ID (with or without a leading #),
+// nickname or species. A species can match several individuals.
+func (c *Client) FindOwned(ref string) []OwnedPokemon {
+	if id, err := strconv.Atoi(strings.TrimPrefix(ref, "#")); err == nil {
+		if owned, ok := c.ownedByID(id); ok {
+			return []OwnedPokemon{owned}
+		}
+		return nil
+	}
+	var byNickname, bySpecies []OwnedPokemon
+	for _, owned := range c.config.Box {
+		if owned.Nickname != "" && strings.EqualFold(owned.Nickname, ref) {
+			byNickname = append(byNickname, owned)
+		}
+		if owned.Species == ref {
+			bySpecies = append(bySpecies, owned)
+		}
+	}
+	if len(byNickname) > 0 {
+		return byNickname
+	}
+	return bySpecies
+}
+
+func (c *Client) ownedByID(id int) (OwnedPokemon, bool) {
+	for _, owned := range c.config.Box {
+		if owned.ID == id {
+			return owned, true
+		}
+	}
+	return OwnedPokemon{}, false
+}
+
+// SetNickname renames the owned Pokemon with the given ID. An empty
+// nickname clears it.
+func (c *Client) SetNickname(id int, nickname string) (OwnedPokemon, error) {
+	for i, owned := range c.config.Box {
+		if owned.ID == id {
+			c.config.Box[i].Nickname = nickname
+			return c.config.Box[i], nil
+		}
+	}
+	return OwnedPokemon{}, fmt.Errorf("you don't own a pokemon #%d", id)
+}
@@ -0,0 +1,69 @@
+package pokeapi
+
+import (
+	"testing"
+)
+
+func TestAddToBox(t *testing.T) {
+	c := newFixtureClient(t)
+	_, err := c.ExploreArea("pastoria-city-area")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	pokemon, err := c.GetPokemon("magikarp")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	first := c.AddToBox(pokemon, 5, "poke-ball")
+	second := c.AddToBox(pokemon, 12, "great-ball")
+
+	if first.ID == second.ID {
+		t.Errorf("expected unique IDs, both were %d", first.ID)
+	}
+	if second.Location != "pastoria-city-area" || second.Level != 12 || second.Ball != "great-ball" {
+		t.Errorf("unexpected owned pokemon %+v", second)
+	}
+	if len(c.Box()) != 2 {
+		t.Errorf("expected 2 pokemon in box, got %d", len(c.Box()))
+	}
+	entry, ok := c.GetPokedexEntry("magikarp")
+	if !ok || !entry.Caught {
+		t.Errorf("expected magikarp caught in pokedex")
+	}
+}
+
+func TestFindOwned(t *testing.T) {
+	c := newFixtureClient(t)
+	pikachu := c.AddToBox(Pokemon{Name: "pikachu"}, 5, "poke-ball")
+	c.AddToBox(Pokemon{Name: "pikachu"}, 7, "poke-ball")
+	ditto := c.AddToBox(Pokemon{Name: "ditto"}, 9, "poke-ball")
+	_, err := c.SetNickname(ditto.ID, "Blob")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cases := []struct {
+		ref  string
+		want int
+	}{
+		{"pikachu", 2},
+		{"#1", 1},
+		{"3", 1},
+		{"blob", 1},
+		{"ditto", 1},
+		{"#42", 0},
+		{"magikarp", 0},
+	}
+	for _, tc := range cases {
+		got := c.FindOwned(tc.ref)
+		if len(got) != tc.want {
+			t.Errorf("%s: expected %d matches, got %d", tc.ref, tc.want, len(got))
+		}
+	}
+	if got := c.FindOwned("#1"); len(got) == 1 && got[0].ID != pikachu.ID {
+		t.Errorf("expected #1 to be the first pikachu, got %+v", got[0])
+	}
+	if _, err := c.SetNickname(42, "Nobody"); err == nil {
+		t.Errorf("expected error renaming a pokemon you don't own")
+	}
+}
@@ -0,0 +1,132 @@
+package pokeapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// A Status condition makes a wild Pokemon easier to catch.
+type Status string
+
+const (
+	StatusNone      Status = ""
+	StatusSleep     Status = "sleep"
+	StatusFreeze    Status = "freeze"
+	StatusParalysis Status = "paralysis"
+	StatusPoison    Status = "poison"
+	StatusBurn      Status = "burn"
+)
+
+func (s Status) catchBonus() float64 {
+	switch s {
+	case StatusSleep, StatusFreeze:
+		return 2
+	case StatusParalysis, StatusPoison, StatusBurn:
+		return 1.5
+	}
+	return 1
+}
+
+// A Ball is named after its PokeAPI item, e.g. "great-ball". Bonus
+// multiplies the catch rate.
+type Ball struct {
+	Name  string
+	Bonus float64
+}
+
+var PokeBall = Ball{Name: "poke-ball", Bonus: 1}
+
+// CatchResult says how a throw went. Shakes is how many times the ball
+// shook before the Pokemon broke free, or 3 if it was caught.
+type CatchResult struct {
+	Pokemon string
+	Caught  bool
+	Shakes  int
+	// Owned is the new individual in the box, if it was caught.
+	Owned OwnedPokemon
+	// Turn is the wild Pokemon's reply if it broke free mid-battle.
+	Turn TurnResult
+}
+
+var ErrNoWild = errors.New("no wild pokemon encountered")
+
+func (c *Client) CatchWild(ball Ball) (CatchResult, error) {
+	return c.CatchWildContext(context.Background(), ball)
+}
+
+// CatchWildContext throws ball from the bag at the wild Pokemon using the
+// generation III/IV formula, and adds it to the box if it's caught. The
+// ball is used up either way. In a battle, the lower the wild Pokemon's HP
+// the better the odds, but if it breaks free it gets a turn to attack.
+func (c *Client) CatchWildContext(ctx context.Context, ball Ball) (CatchResult, error) {
+	if c.wild == nil {
+		return CatchResult{}, ErrNoWild
+	}
+	if c.battle != nil && c.battle.active.Fainted() {
+		return CatchResult{}, fmt.Errorf("%s has fainted, switch to another pokemon", c.battle.active.Name)
+	}
+	if c.config.Bag[ball.Name] <= 0 {
+		return CatchResult{}, fmt.Errorf("you have no %s left", ball.Name)
+	}
+	pokemon, err := c.GetPokemonContext(ctx, c.wild.Pokemon)
+	if err != nil {
+		return CatchResult{}, err
+	}
+	species, err := c.GetSpeciesContext(ctx, pokemon.Species.Name)
+	if err != nil {
+		return CatchResult{}, err
+	}
+	// Only spend the ball once nothing else can fail.
+	c.config.Bag[ball.Name]--
+	if c.wild.MaxHP == 0 {
+		c.wild.MaxHP = hpStat(pokemon, c.wild.Level)
+		c.wild.HP = c.wild.MaxHP
+	}
+	wild := *c.wild
+	a := catchValue(species.CaptureRate, wild.MaxHP, wild.HP, ball, wild.Status)
+	caught, shakes := shakeChecks(a, func() int { return c.rand.Intn(65536) })
+	result := CatchResult{Pokemon: wild.Pokemon, Caught: caught, Shakes: shakes}
+	switch {
+	case caught:
+		c.EndEncounter()
+		result.Owned = c.AddToBox(pokemon, wild.Level, ball.Name)
+	case c.battle != nil:
+		result.Turn = c.wildTurn()
+	}
+	return result, nil
+}
+
+// hpStat is a Pokemon's max HP at level. Wild Pokemon here have no IVs or
+// EVs, so it's the base stat alone.
+func hpStat(p Pokemon, level int) int {
+	base := 0
+	for _, stat := range p.Stats {
+		if stat.Stat.Name == "hp" {
+			base = stat.BaseStat
+		}
+	}
+	return 2*base*level/100 + level + 10
+}
+
+// catchValue is the modified catch rate "a": 255 or more is a sure catch.
+func catchValue(captureRate, maxHP, hp int, ball Ball, status Status) int {
+	a := float64((3*maxHP-2*hp)*captureRate) / float64(3*maxHP) * ball.Bonus * status.catchBonus()
+	return max(1, int(a))
+}
+
+// shakeChecks makes the four checks of 65536 that the ball must pass to
+// hold. roll returns a number in [0, 65536).
+func shakeChecks(a int, roll func() int) (caught bool, shakes int) {
+	if a >= 255 {
+		return true, 3
+	}
+	b := int(1048560 / math.Sqrt(math.Sqrt(16711680/float64(a))))
+	for i := range 4 {
+		if roll() >= b {
+			return false, i
+		}
+	}
+	return true, 3
+}
@@ -0,0 +1,142 @@
+package pokeapi
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCatchValue(t *testing.T) {
+	cases := []struct {
+		name        string
+		captureRate int
+		maxHP, hp   int
+		ball        Ball
+		status      Status
+		want        int
+	}{
+		{"full hp", 255, 30, 30, PokeBall, StatusNone, 85},
+		{"one hp", 255, 30, 1, PokeBall, StatusNone, 249},
+		{"hard to catch", 35, 30, 30, PokeBall, StatusNone, 11},
+		{"asleep", 35, 30, 30, PokeBall, StatusSleep, 23},
+		{"paralyzed", 35, 30, 30, PokeBall, StatusParalysis, 17},
+		{"better ball", 35, 30, 30, Ball{Name: "ultra-ball", Bonus: 2}, StatusNone, 23},
+		{"never below one", 3, 300, 300, PokeBall, StatusNone, 1},
+	}
+	for _, tc := range cases {
+		got := catchValue(tc.captureRate, tc.maxHP, tc.hp, tc.ball, tc.status)
+		if got != tc.want {
+			t.Errorf("%s: expected %d, got %d", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestShakeChecks(t *testing.T) {
+	// With a = 85 each check passes on rolls below about 49,800.
+	rolls := func(values ...int) func() int {
+		return func() int {
+			v := values[0]
+			values = values[1:]
+			return v
+		}
+	}
+	cases := []struct {
+		name       string
+		a          int
+		roll       func() int
+		wantCaught bool
+		wantShakes int
+	}{
+		{"sure catch", 255, nil, true, 3},
+		{"breaks free at once", 85, rolls(60000), false, 0},
+		{"two shakes", 85, rolls(0, 0, 60000), false, 2},
+		{"last check fails", 85, rolls(0, 0, 0, 60000), false, 3},
+		{"all checks pass", 85, rolls(0, 0, 0, 0), true, 3},
+	}
+	for _, tc := range cases {
+		caught, shakes := shakeChecks(tc.a, tc.roll)
+		if caught != tc.wantCaught || shakes != tc.wantShakes {
+			t.Errorf("%s: expected caught %v with %d shakes, got %v with %d", tc.name, tc.wantCaught, tc.wantShakes, caught, shakes)
+		}
+	}
+}
+
+func TestHPStat(t *testing.T) {
+	c := newFixtureClient(t)
+	magikarp, err := c.GetPokemon("magikarp")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Base HP 20 at level 10: 2*20*10/100 + 10 + 10.
+	if got := hpStat(magikarp, 10); got != 24 {
+		t.Errorf("expected 24, got %d", got)
+	}
+}
+
+func TestCatchWild(t *testing.T) {
+	c := newFixtureClient(t)
+	_, err := c.CatchWild(PokeBall)
+	if !errors.Is(err, ErrNoWild) {
+		t.Errorf("expected ErrNoWild, got %v", err)
+	}
+	_, err = c.ExploreArea("pastoria-city-area")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wild, err := c.Encounter("old-rod", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Magikarp has the highest capture rate, so this won't take long.
+	c.config.Bag["poke-ball"] = 100
+	for range 100 {
+		result, err := c.CatchWild(PokeBall)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Shakes < 0 || result.Shakes > 3 {
+			t.Errorf("expected 0-3 shakes, got %d", result.Shakes)
+		}
+		if !result.Caught {
+			continue
+		}
+		if result.Owned.Species != "magikarp" || result.Owned.Level != wild.Level {
+			t.Errorf("unexpected owned pokemon %+v", result.Owned)
+		}
+		if _, ok := c.Wild(); ok {
+			t.Errorf("expected the encounter to end once caught")
+		}
+		return
+	}
+	t.Errorf("expected to catch magikarp within 100 throws")
+}
+
+func TestCatchWildUsesBalls(t *testing.T) {
+	c := newFixtureClient(t)
+	_, err := c.ExploreArea("pastoria-city-area")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = c.Encounter("old-rod", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	masterBall, ok := BallByName("master-ball")
+	if !ok {
+		t.Fatalf("expected master-ball to be a ball")
+	}
+	_, err = c.CatchWild(masterBall)
+	if err == nil {
+		t.Fatalf("expected error throwing a ball you don't have")
+	}
+	c.config.Bag["master-ball"] = 1
+	result, err := c.CatchWild(masterBall)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Caught || result.Owned.Ball != "master-ball" {
+		t.Errorf("expected a master ball to always catch, got %+v", result)
+	}
+	if _, ok := c.Bag()["master-ball"]; ok {
+		t.Errorf("expected the master ball to be used up")
+	}
+}
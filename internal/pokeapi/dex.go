@@ -0,0 +1,80 @@
+package pokeapi
+
+import (
+	"context"
+	"fmt"
+)
+
+// A RegionalDex is one of PokeAPI's /pokedex lists, e.g. "kanto" or
+// "national": the species a game expects you to complete.
+type RegionalDex struct {
+	ID             int    `json:"id"`
+	Name           string `json:"name"`
+	IsMainSeries   bool   `json:"is_main_series"`
+	PokemonEntries []struct {
+		EntryNumber    int `json:"entry_number"`
+		PokemonSpecies struct {
+			Name string `json:"name"`
+			URL  string `json:"url"`
+		} `json:"pokemon_species"`
+	} `json:"pokemon_entries"`
+}
+
+func (c *Client) GetRegionalDex(name string) (RegionalDex, error) {
+	return c.GetRegionalDexContext(context.Background(), name)
+}
+
+func (c *Client) GetRegionalDexContext(ctx context.Context, name string) (RegionalDex, error) {
+	var dex RegionalDex
+	url := fmt.Sprintf("%s/pokedex/%s", c.apiUrl, name)
+	err := c.getJSON(ctx, url, &dex)
+	if err != nil {
+		return RegionalDex{}, notFoundAs(err, "pokedex", name)
+	}
+	return dex, nil
+}
+
+// Progress counts how much of a regional dex you have seen and caught.
+type Progress struct {
+	Dex    string
+	Total  int
+	Seen   int
+	Caught int
+}
+
+func (c *Client) Progress(dexName string) (Progress, error) {
+	return c.ProgressContext(context.Background(), dexName)
+}
+
+// ProgressContext matches the dex's species against your Pokedex by name.
+// Encounters name Pokemon rather than species, which only differ for
+// alternate forms like wormadam-plant, so those don't count.
+func (c *Client) ProgressContext(ctx context.Context, dexName string) (Progress, error) {
+	dex, err := c.GetRegionalDexContext(ctx, dexName)
+	if err != nil {
+		return Progress{}, err
+	}
+	p := Progress{Dex: dex.Name, Total: len(dex.PokemonEntries)}
+	for _, entry := range dex.PokemonEntries {
+		known, ok := c.config.Pokedex[entry.PokemonSpecies.Name]
+		if !ok {
+			continue
+		}
+		if known.Seen {
+			p.Seen++
+		}
+		if known.Caught {
+			p.Caught++
+		}
+	}
+	return p, nil
+}
+
+// markSeen records a species as seen without touching what's known about
+// it if it was caught already.
+func (c *Client) markSeen(name string) {
+	if _, ok := c.config.Pokedex[name]; ok {
+		return
+	}
+	c.config.Pokedex[name] = PokedexEntry{Name: name, Seen: true}
+}
@@ -0,0 +1,50 @@
+package pokeapi
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestProgress(t *testing.T) {
+	c := newFixtureClient(t)
+	// Pastoria has tentacool, tentacruel, magikarp and psyduck from Kanto,
+	// plus buizel, which isn't in the Kanto dex.
+	_, err := c.ExploreArea("pastoria-city-area")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	magikarp, err := c.GetPokemon("magikarp")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	c.AddToBox(magikarp, 5, "poke-ball")
+
+	p, err := c.Progress("kanto")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := Progress{Dex: "kanto", Total: 151, Seen: 4, Caught: 1}
+	if p != want {
+		t.Errorf("expected %+v, got %+v", want, p)
+	}
+	entry, ok := c.GetPokedexEntry("buizel")
+	if !ok || !entry.Seen || entry.Caught {
+		t.Errorf("expected buizel seen but not caught, got %+v", entry)
+	}
+	// Exploring again mustn't forget magikarp was caught.
+	_, err = c.ExploreArea("pastoria-city-area")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if entry, _ := c.GetPokedexEntry("magikarp"); !entry.Caught {
+		t.Errorf("expected magikarp still caught after exploring again")
+	}
+}
+
+func TestProgressUnknownDex(t *testing.T) {
+	c := newFixtureClient(t)
+	_, err := c.Progress("johto")
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
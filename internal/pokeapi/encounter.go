@@ -0,0 +1,168 @@
+package pokeapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// An Encounter is a wild Pokemon you ran into. It's the only Pokemon you
+// can try to catch until you catch it or move on.
+type Encounter struct {
+	Pokemon string
+	Level   int
+	Method  string
+	Version string
+	Area    string
+	// MaxHP stays 0 until the Pokemon's stats are needed.
+	MaxHP  int
+	HP     int
+	Status Status
+}
+
+// DefaultEncounterMethod is walking through tall grass.
+const DefaultEncounterMethod = "walk"
+
+var ErrNoArea = errors.New("no area explored yet")
+
+// An encounterSlot is one way a Pokemon can show up, weighted by chance.
+type encounterSlot struct {
+	pokemon  string
+	chance   int
+	minLevel int
+	maxLevel int
+}
+
+func (c *Client) Encounter(method, version string) (Encounter, error) {
+	return c.EncounterContext(context.Background(), method, version)
+}
+
+// EncounterContext rolls a wild Pokemon in the area explored last, weighted
+// by PokeAPI's encounter chances for method and version. An empty method
+// means walking, and an empty version means the first version the area has
+// data for.
+func (c *Client) EncounterContext(ctx context.Context, method, version string) (Encounter, error) {
+	if c.config.Area == "" {
+		return Encounter{}, ErrNoArea
+	}
+	if c.battle != nil {
+		return Encounter{}, ErrInBattle
+	}
+	if method == "" {
+		method = DefaultEncounterMethod
+	}
+	var location LocationArea
+	url := fmt.Sprintf("%s/location-area/%s", c.apiUrl, c.config.Area)
+	err := c.getJSON(ctx, url, &location)
+	if err != nil {
+		return Encounter{}, notFoundAs(err, "area", c.config.Area)
+	}
+	slots, version, err := encounterSlots(location, method, version)
+	if err != nil {
+		return Encounter{}, err
+	}
+	total := 0
+	for _, slot := range slots {
+		total += slot.chance
+	}
+	slot := pickSlot(slots, c.rand.Intn(total))
+	wild := Encounter{
+		Pokemon: slot.pokemon,
+		Level:   slot.minLevel + c.rand.Intn(slot.maxLevel-slot.minLevel+1),
+		Method:  method,
+		Version: version,
+		Area:    c.config.Area,
+	}
+	c.wild = &wild
+	c.markSeen(wild.Pokemon)
+	return wild, nil
+}
+
+// encounterSlots collects the slots for method in version, picking a
+// version if none was given. The error lists what the area does have.
+func encounterSlots(location LocationArea, method, version string) ([]encounterSlot, string, error) {
+	var slots []encounterSlot
+	var methods, versions []string
+	for _, encounter := range location.PokemonEncounters {
+		for _, vd := range encounter.VersionDetails {
+			for _, detail := range vd.EncounterDetails {
+				if !slices.Contains(methods, detail.Method.Name) {
+					methods = append(methods, detail.Method.Name)
+				}
+				if detail.Method.Name != method {
+					continue
+				}
+				if !slices.Contains(versions, vd.Version.Name) {
+					versions = append(versions, vd.Version.Name)
+				}
+				if version == "" {
+					version = vd.Version.Name
+				}
+				if vd.Version.Name != version || detail.Chance <= 0 {
+					continue
+				}
+				slots = append(slots, encounterSlot{
+					pokemon:  encounter.Pokemon.Name,
+					chance:   detail.Chance,
+					minLevel: detail.MinLevel,
+					maxLevel: max(detail.MaxLevel, detail.MinLevel),
+				})
+			}
+		}
+	}
+	switch {
+	case len(methods) == 0:
+		return nil, "", fmt.Errorf("no wild pokemon in %s", location.Name)
+	case len(versions) == 0:
+		return nil, "", fmt.Errorf("no %s encounters in %s, try %s", method, location.Name, strings.Join(methods, ", "))
+	case len(slots) == 0:
+		return nil, "", fmt.Errorf("no %s encounters in %s in %s, try %s", method, location.Name, version, strings.Join(versions, ", "))
+	}
+	return slots, version, nil
+}
+
+// pickSlot returns the slot roll lands in, for roll in [0, total chance).
+func pickSlot(slots []encounterSlot, roll int) encounterSlot {
+	for _, slot := range slots {
+		if roll < slot.chance {
+			return slot
+		}
+		roll -= slot.chance
+	}
+	return slots[len(slots)-1]
+}
+
+// Wild returns the Pokemon currently encountered, if any.
+func (c *Client) Wild() (Encounter, bool) {
+	if c.wild == nil {
+		return Encounter{}, false
+	}
+	return *c.wild, true
+}
+
+// WildNamed returns the wild Pokemon if it's called name. Otherwise it
+// returns a NotFoundError, suggesting the wild Pokemon's name if name looks
+// like a typo of it.
+func (c *Client) WildNamed(name string) (Encounter, error) {
+	if c.wild == nil {
+		return Encounter{}, &NotFoundError{Resource: "wild pokemon", Name: name, Err: ErrNoWild}
+	}
+	if c.wild.Pokemon != name {
+		return Encounter{}, &NotFoundError{
+			Resource:    "wild pokemon",
+			Name:        name,
+			Suggestions: suggest(name, []string{c.wild.Pokemon}),
+			Err:         fmt.Errorf("the wild pokemon is %s", c.wild.Pokemon),
+		}
+	}
+	return *c.wild, nil
+}
+
+// EndEncounter lets the wild Pokemon go, e.g. once it's caught, and ends
+// any battle with it.
+func (c *Client) EndEncounter() {
+	c.wild = nil
+	c.battle = nil
+}
@@ -0,0 +1,121 @@
+package pokeapi
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestEncounter(t *testing.T) {
+	c := newFixtureClient(t)
+	_, err := c.Encounter("", "")
+	if !errors.Is(err, ErrNoArea) {
+		t.Errorf("expected ErrNoArea before exploring, got %v", err)
+	}
+	_, err = c.ExploreArea("eterna-forest-area")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	forest := []string{"bidoof", "kricketot", "wurmple", "silcoon", "cascoon", "budew", "buneary", "hoothoot", "gastly"}
+	for range 50 {
+		wild, err := c.Encounter("", "platinum")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !slices.Contains(forest, wild.Pokemon) {
+			t.Errorf("unexpected %s in eterna forest", wild.Pokemon)
+		}
+		// Bidoof and kricketot don't appear in platinum.
+		if wild.Pokemon == "bidoof" || wild.Pokemon == "kricketot" {
+			t.Errorf("unexpected %s in platinum", wild.Pokemon)
+		}
+		if wild.Level < 9 || wild.Level > 12 {
+			t.Errorf("expected level 9-12, got %d", wild.Level)
+		}
+		if wild.Method != "walk" || wild.Version != "platinum" || wild.Area != "eterna-forest-area" {
+			t.Errorf("unexpected encounter %+v", wild)
+		}
+		if got, ok := c.Wild(); !ok || got != wild {
+			t.Errorf("expected %+v to be the wild pokemon, got %+v", wild, got)
+		}
+	}
+
+	_, err = c.ExploreArea("pastoria-city-area")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := c.Wild(); ok {
+		t.Errorf("expected exploring to end the encounter")
+	}
+	if _, err := c.Encounter("walk", ""); err == nil {
+		t.Errorf("expected error walking in pastoria city, which only has water")
+	}
+	wild, err := c.Encounter("old-rod", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if wild.Pokemon != "magikarp" || wild.Version != "diamond" {
+		t.Errorf("expected magikarp in diamond, got %+v", wild)
+	}
+	if _, err := c.Encounter("old-rod", "emerald"); err == nil {
+		t.Errorf("expected error for a version with no data")
+	}
+}
+
+func TestWildNamed(t *testing.T) {
+	c := newFixtureClient(t)
+	if _, err := c.WildNamed("magikarp"); !errors.Is(err, ErrNoWild) {
+		t.Errorf("expected ErrNoWild before an encounter, got %v", err)
+	}
+	c.wild = &Encounter{Pokemon: "magikarp", Level: 5}
+	cases := []struct {
+		name            string
+		wantErr         bool
+		wantSuggestions []string
+	}{
+		{name: "magikarp"},
+		{name: "magikrap", wantErr: true, wantSuggestions: []string{"magikarp"}},
+		{name: "pikachu", wantErr: true},
+	}
+	for _, tc := range cases {
+		wild, err := c.WildNamed(tc.name)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("%s: expected error=%v, got %v", tc.name, tc.wantErr, err)
+			continue
+		}
+		if !tc.wantErr {
+			if wild.Pokemon != tc.name {
+				t.Errorf("%s: expected the wild pokemon, got %+v", tc.name, wild)
+			}
+			continue
+		}
+		var notFound *NotFoundError
+		if !errors.As(err, &notFound) || !slices.Equal(notFound.Suggestions, tc.wantSuggestions) {
+			t.Errorf("%s: expected suggestions %v, got %v", tc.name, tc.wantSuggestions, err)
+		}
+	}
+}
+
+func TestPickSlot(t *testing.T) {
+	slots := []encounterSlot{
+		{pokemon: "tentacool", chance: 60},
+		{pokemon: "psyduck", chance: 30},
+		{pokemon: "buizel", chance: 10},
+	}
+	cases := []struct {
+		roll int
+		want string
+	}{
+		{0, "tentacool"},
+		{59, "tentacool"},
+		{60, "psyduck"},
+		{89, "psyduck"},
+		{90, "buizel"},
+		{99, "buizel"},
+	}
+	for _, tc := range cases {
+		if got := pickSlot(slots, tc.roll); got.pokemon != tc.want {
+			t.Errorf("roll %d: expected %s, got %s", tc.roll, tc.want, got.pokemon)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package pokeapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Sentinel errors for classifying failures with errors.Is. The concrete
+// errors returned by the client carry more detail and can be pulled out
+// with errors.As.
+var (
+	ErrNotFound    = errors.New("not found")
+	ErrRateLimited = errors.New("rate limited by API")
+	ErrServer      = errors.New("API server error")
+	ErrNetwork     = errors.New("network error")
+	ErrDecode      = errors.New("can't decode API response")
+)
+
+type APIError struct {
+	// StatusCode is zero when no complete response was received, e.g. the
+	// connection failed or the body couldn't be read.
+	StatusCode int
+	Attempts   int
+	Err        error
+}
+
+func (e *APIError) Error() string {
+	if e.Attempts > 1 {
+		return fmt.Sprintf("call to API failed after %d attempts: %v", e.Attempts, e.Err)
+	}
+	return fmt.Sprintf("call to API failed: %v", e.Err)
+}
+
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrServer:
+		return e.StatusCode >= 500
+	case ErrNetwork:
+		// A cancelled request is the caller's doing, not the network's.
+		return e.StatusCode == 0 &&
+			!errors.Is(e.Err, context.Canceled) &&
+			!errors.Is(e.Err, context.DeadlineExceeded)
+	}
+	return false
+}
+
+// NotFoundError reports that the API has no resource with the given name,
+// e.g. a misspelled Pokemon. Suggestions holds similar names that do exist,
+// best first.
+type NotFoundError struct {
+	Resource    string
+	Name        string
+	Suggestions []string
+	Err         error
+}
+
+func (e *NotFoundError) Error() string {
+	if len(e.Suggestions) > 0 {
+		return fmt.Sprintf("no such %s '%s', did you mean %s?", e.Resource, e.Name, strings.Join(e.Suggestions, ", "))
+	}
+	return fmt.Sprintf("no such %s '%s'", e.Resource, e.Name)
+}
+
+func (e *NotFoundError) Unwrap() error {
+	return e.Err
+}
+
+func (e *NotFoundError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
+// DecodeError reports a response body, fresh or cached, that isn't the JSON
+// we expected.
+type DecodeError struct {
+	URL string
+	Err error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("can't decode response from %s: %v", e.URL, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+func (e *DecodeError) Is(target error) bool {
+	return target == ErrDecode
+}
+
+// notFoundAs turns a 404 into a NotFoundError naming what was looked up,
+// and leaves other errors alone.
+func notFoundAs(err error, resource, name string) error {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+		return &NotFoundError{Resource: resource, Name: name, Err: err}
+	}
+	return err
+}
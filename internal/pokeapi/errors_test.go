@@ -0,0 +1,47 @@
+package pokeapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestErrorClassification(t *testing.T) {
+	sentinels := []error{ErrNotFound, ErrRateLimited, ErrServer, ErrNetwork, ErrDecode}
+	cases := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{name: "404", err: &APIError{StatusCode: 404, Err: errors.New("x")}, want: ErrNotFound},
+		{name: "429", err: &APIError{StatusCode: 429, Err: errors.New("x")}, want: ErrRateLimited},
+		{name: "503", err: &APIError{StatusCode: 503, Err: errors.New("x")}, want: ErrServer},
+		{name: "no response", err: &APIError{Err: errors.New("connection refused")}, want: ErrNetwork},
+		{name: "cancelled", err: &APIError{Err: fmt.Errorf("can't get: %w", context.Canceled)}, want: nil},
+		{name: "400", err: &APIError{StatusCode: 400, Err: errors.New("x")}, want: nil},
+		{name: "decode", err: &DecodeError{URL: "u", Err: errors.New("x")}, want: ErrDecode},
+		{name: "not found wrapper", err: notFoundAs(&APIError{StatusCode: 404, Err: errors.New("x")}, "pokemon", "pikachuu"), want: ErrNotFound},
+	}
+	for _, c := range cases {
+		wrapped := fmt.Errorf("command failed: %w", c.err)
+		for _, sentinel := range sentinels {
+			got := errors.Is(wrapped, sentinel)
+			if got != (sentinel == c.want) {
+				t.Errorf("%s: errors.Is(%v) = %v", c.name, sentinel, got)
+			}
+		}
+	}
+}
+
+func TestGetPokemonNotFound(t *testing.T) {
+	c := newFixtureClient(t)
+	_, err := c.GetPokemon("missingno")
+	var notFound *NotFoundError
+	if !errors.As(err, &notFound) {
+		t.Fatalf("expected NotFoundError, got %v", err)
+	}
+	if notFound.Name != "missingno" || notFound.Resource != "pokemon" {
+		t.Errorf("unexpected NotFoundError %+v", notFound)
+	}
+}
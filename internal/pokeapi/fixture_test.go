@@ -0,0 +1,76 @@
+package pokeapi
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFixtureName(t *testing.T) {
+	cases := []struct {
+		url  string
+		want string
+	}{
+		{url: "https://pokeapi.co/api/v2/pokemon/ditto", want: "pokeapi.co_api_v2_pokemon_ditto.json"},
+		{url: "https://pokeapi.co/api/v2/location-area?offset=20&limit=20", want: "pokeapi.co_api_v2_location-area_offset_20_limit_20.json"},
+		{url: "https://pokeapi.co/api/v2/location-area/", want: "pokeapi.co_api_v2_location-area.json"},
+	}
+	for _, c := range cases {
+		got := fixtureName(c.url)
+		if got != c.want {
+			t.Errorf("fixtureName(%q): expected %s, got %s", c.url, c.want, got)
+		}
+	}
+}
+
+func TestRecordReplay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/pokemon/ditto" {
+			w.Write([]byte(`{"name":"ditto"}`))
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+	dir := t.TempDir()
+
+	recorder := &http.Client{Transport: NewRecordTransport(dir, nil)}
+	for _, path := range []string{"/pokemon/ditto", "/pokemon/missingno"} {
+		resp, err := recorder.Get(server.URL + path)
+		if err != nil {
+			t.Fatalf("unexpected error recording %s: %v", path, err)
+		}
+		resp.Body.Close()
+	}
+	server.Close()
+
+	replayer := &http.Client{Transport: NewReplayTransport(dir)}
+	cases := []struct {
+		path       string
+		wantStatus int
+		wantBody   string
+	}{
+		{path: "/pokemon/ditto", wantStatus: http.StatusOK, wantBody: `{"name":"ditto"}`},
+		{path: "/pokemon/missingno", wantStatus: http.StatusNotFound, wantBody: "404 page not found\n"},
+	}
+	for _, c := range cases {
+		resp, err := replayer.Get(server.URL + c.path)
+		if err != nil {
+			t.Fatalf("unexpected error replaying %s: %v", c.path, err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != c.wantStatus {
+			t.Errorf("%s: expected status %d, got %d", c.path, c.wantStatus, resp.StatusCode)
+		}
+		if string(body) != c.wantBody {
+			t.Errorf("%s: expected body %q, got %q", c.path, c.wantBody, body)
+		}
+	}
+
+	_, err := replayer.Get(server.URL + "/pokemon/unknown")
+	if err == nil {
+		t.Errorf("expected error replaying unrecorded URL")
+	}
+}
@@ -0,0 +1,81 @@
+package pokeapi
+
+import (
+	"context"
+	"fmt"
+	"slices"
+)
+
+// Move is the part of PokeAPI's /move resource battles need. Power and
+// Accuracy are null in PokeAPI for moves that deal no damage or never
+// miss, which decode as 0.
+type Move struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	Power    int    `json:"power"`
+	Accuracy int    `json:"accuracy"`
+	PP       int    `json:"pp"`
+	Priority int    `json:"priority"`
+	Type     struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	} `json:"type"`
+	DamageClass struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	} `json:"damage_class"`
+	// Meta.Ailment is the status condition the move can cause, "none" if
+	// it can't. An AilmentChance of 0 means every hit causes it.
+	Meta struct {
+		Ailment struct {
+			Name string `json:"name"`
+			URL  string `json:"url"`
+		} `json:"ailment"`
+		AilmentChance int `json:"ailment_chance"`
+	} `json:"meta"`
+}
+
+func (c *Client) GetMove(name string) (Move, error) {
+	return c.GetMoveContext(context.Background(), name)
+}
+
+func (c *Client) GetMoveContext(ctx context.Context, name string) (Move, error) {
+	var move Move
+	url := fmt.Sprintf("%s/move/%s", c.apiUrl, name)
+	err := c.getJSON(ctx, url, &move)
+	if err != nil {
+		return Move{}, notFoundAs(err, "move", name)
+	}
+	return move, nil
+}
+
+// maxMoves is how many moves a Pokemon can know at once.
+const maxMoves = 4
+
+// learnedMoves returns the moves p knows at level: the last four it learned
+// by levelling up, as a Pokemon nobody has taught anything would.
+func learnedMoves(p Pokemon, level int) []string {
+	type learned struct {
+		name  string
+		level int
+	}
+	var moves []learned
+	for _, m := range p.Moves {
+		for _, detail := range m.VersionGroupDetails {
+			if detail.MoveLearnMethod.Name == "level-up" && detail.LevelLearnedAt <= level {
+				moves = append(moves, learned{m.Move.Name, detail.LevelLearnedAt})
+				break
+			}
+		}
+	}
+	// Stable, so moves learned at the same level keep PokeAPI's order.
+	slices.SortStableFunc(moves, func(a, b learned) int { return a.level - b.level })
+	if len(moves) > maxMoves {
+		moves = moves[len(moves)-maxMoves:]
+	}
+	names := make([]string, len(moves))
+	for i, m := range moves {
+		names[i] = m.name
+	}
+	return names
+}
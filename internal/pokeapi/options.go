@@ -0,0 +1,106 @@
+package pokeapi
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/tquid/pokedexcli/internal/pokecache"
+)
+
+type Option func(*Client)
+
+// WithBaseURL points the client at a PokeAPI v2 compatible server, e.g. a
+// self-hosted mirror or an httptest.Server.
+func WithBaseURL(url string) Option {
+	return func(c *Client) {
+		c.apiUrl = strings.TrimRight(url, "/")
+	}
+}
+
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithTimeout limits how long a single API request may take.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+// WithCache makes the client use an existing cache instead of creating its
+// own. The caller remains responsible for closing it.
+func WithCache(cache *pokecache.Cache) Option {
+	return func(c *Client) {
+		c.cache = cache
+	}
+}
+
+// WithDiskCache keeps API responses under dir as well as in memory, so they
+// survive between runs. It has no effect when combined with WithCache.
+func WithDiskCache(dir string) Option {
+	return func(c *Client) {
+		c.cacheDir = dir
+	}
+}
+
+// WithSavePath overrides where Save and NewClient read and write the
+// Pokedex save file.
+func WithSavePath(path string) Option {
+	return func(c *Client) {
+		c.savePath = path
+	}
+}
+
+func DefaultCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("can't find user cache dir: %w", err)
+	}
+	return filepath.Join(dir, "pokedexcli"), nil
+}
+
+// WithRetry sets how transient API failures are retried. Pass NoRetry to
+// fail on the first error.
+func WithRetry(policy RetryPolicy) Option {
+	return func(c *Client) {
+		if policy.MaxAttempts < 1 {
+			policy.MaxAttempts = 1
+		}
+		c.retry = policy
+	}
+}
+
+// WithRateLimit caps requests to rps per second on average, allowing bursts
+// of up to burst requests. A non-positive rps disables limiting.
+func WithRateLimit(rps float64, burst int) Option {
+	return func(c *Client) {
+		if rps <= 0 {
+			c.limiter = nil
+			return
+		}
+		c.limiter = newRateLimiter(rps, burst)
+	}
+}
+
+// WithSeed starts the random source from seed, so encounters and catches
+// come out the same every time.
+func WithSeed(seed int64) Option {
+	return func(c *Client) {
+		c.SetSeed(seed)
+	}
+}
+
+// WithRand replaces the random source, e.g. with one that always rolls the
+// same number in tests.
+func WithRand(r Rand) Option {
+	return func(c *Client) {
+		c.rand = r
+	}
+}
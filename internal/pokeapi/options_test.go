@@ -0,0 +1,37 @@
+package pokeapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+func TestWithBaseURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2/pokemon/pikachu" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"name": "pikachu", "base_experience": 112}`))
+	}))
+	defer server.Close()
+
+	c, err := NewClient(
+		WithBaseURL(server.URL+"/api/v2/"),
+		WithHTTPClient(server.Client()),
+		WithSavePath(filepath.Join(t.TempDir(), "save.json")),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer c.Close()
+
+	pokemon, err := c.GetPokemon("pikachu")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pokemon.BaseExperience != 112 {
+		t.Errorf("expected base experience 112, got %d", pokemon.BaseExperience)
+	}
+}
@@ -1,11 +1,15 @@
 package pokeapi
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"net/http"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/tquid/pokedexcli/internal/pokecache"
@@ -76,36 +80,62 @@ type LocationArea struct {
 	} `json:"pokemon_encounters"`
 }
 
-type APIError struct {
-	StatusCode int
-	Err        error
+// A PokedexEntry records what you know about a species. Pokemon holds the
+// species data once one has been caught, so inspect works offline.
+type PokedexEntry struct {
+	Name    string   `json:"name"`
+	Seen    bool     `json:"seen"`
+	Caught  bool     `json:"caught"`
+	Pokemon *Pokemon `json:"pokemon,omitempty"`
 }
 
-func (e *APIError) Error() string {
-	return fmt.Sprintf("call to API failed: %v", e.Err)
-}
-
-func (e *APIError) Unwrap() error {
-	return e.Err
-}
-
-type Pokedex map[string]Pokemon
+type Pokedex map[string]PokedexEntry
 
 type Config struct {
 	Count    int                `json:"count"`
 	Next     string             `json:"next"`
 	Previous string             `json:"previous"`
 	Results  []LocationAreaPage `json:"results"`
-	Pokedex  Pokedex            `json:"pokedex"`
+	// Area is the location area explored last, where catches happen.
+	Area        string         `json:"area"`
+	Pokedex     Pokedex        `json:"pokedex"`
+	Box         []OwnedPokemon `json:"box"`
+	LastOwnedID int            `json:"last_owned_id"`
+	Bag         Bag            `json:"bag"`
 }
 
+// Pokemon payloads run to a few hundred KB each, so this holds a few hundred
+// of them before the least recently used are evicted.
+const defaultCacheMaxBytes = 64 << 20
+
+const defaultBaseURL = "https://pokeapi.co/api/v2"
+
+// PokeAPI has no hard limit, but asks clients to be gentle.
+const (
+	defaultRateLimit = 10
+	defaultRateBurst = 10
+)
+
 type Client struct {
-	config *Config
-	apiUrl string
-	cache  *pokecache.Cache
+	config     *Config
+	apiUrl     string
+	httpClient *http.Client
+	timeout    time.Duration
+	cache      *pokecache.Cache
+	ownsCache  bool
+	retry      RetryPolicy
+	limiter    *rateLimiter
+	inflight   flightGroup
+	savePath   string
+	cacheDir   string
+	// wild is the Pokemon encountered last, until it's caught or you move.
+	wild   *Encounter
+	battle *battle
+	rand   Rand
+	seed   int64
 }
 
-func NewClient() *Client {
+func NewClient(opts ...Option) (*Client, error) {
 	client := &Client{
 		config: &Config{
 			Count:    0,
@@ -113,11 +143,53 @@ func NewClient() *Client {
 			Previous: "",
 			Results:  nil,
 			Pokedex:  make(Pokedex),
+			Bag:      startingBag(),
 		},
-		apiUrl: "https://pokeapi.co/api/v2",
-		cache:  pokecache.NewCache(time.Minute * 5),
+		apiUrl:     defaultBaseURL,
+		httpClient: http.DefaultClient,
+		retry:      DefaultRetryPolicy,
+		limiter:    newRateLimiter(defaultRateLimit, defaultRateBurst),
+	}
+	client.SetSeed(newSeed())
+	for _, opt := range opts {
+		opt(client)
+	}
+	if client.savePath == "" {
+		savePath, err := defaultSavePath()
+		if err != nil {
+			return nil, err
+		}
+		client.savePath = savePath
+	}
+	if client.timeout > 0 {
+		// Copy so we don't change the timeout on a caller's shared client.
+		httpClient := *client.httpClient
+		httpClient.Timeout = client.timeout
+		client.httpClient = &httpClient
+	}
+	if client.cache == nil {
+		cacheOpts := []pokecache.Option{pokecache.WithMaxBytes(defaultCacheMaxBytes)}
+		if client.cacheDir != "" {
+			cacheOpts = append(cacheOpts, pokecache.WithDir(client.cacheDir))
+		}
+		client.cache = pokecache.NewCache(time.Minute*5, cacheOpts...)
+		client.ownsCache = true
+	}
+	err := client.loadSave()
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+	return client, nil
+}
+
+// Close releases the client's background resources. A cache passed in with
+// WithCache is left for the caller to close. The client must not be used
+// afterwards.
+func (c *Client) Close() {
+	if c.ownsCache {
+		c.cache.Close()
 	}
-	return client
 }
 
 func (c *Client) IsNew() bool {
@@ -127,111 +199,141 @@ func (c *Client) IsNew() bool {
 	return false
 }
 
-func (c *Client) callAPI(url string) ([]byte, error) {
-	resp, err := http.Get(url)
+// callAPI GETs url. Concurrent calls for the same url share one request.
+func (c *Client) callAPI(ctx context.Context, url string) ([]byte, error) {
+	return c.inflight.do(ctx, url, func() ([]byte, error) {
+		return c.callAPIWithRetry(ctx, url)
+	})
+}
+
+// callAPIWithRetry GETs url, retrying transient failures according to
+// c.retry.
+func (c *Client) callAPIWithRetry(ctx context.Context, url string) ([]byte, error) {
+	for attempt := 1; ; attempt++ {
+		body, retryAfter, err := c.callAPIOnce(ctx, url)
+		if err == nil {
+			return body, nil
+		}
+		var apiErr *APIError
+		if errors.As(err, &apiErr) {
+			apiErr.Attempts = attempt
+		}
+		if attempt >= c.retry.MaxAttempts || !retryable(ctx, err) {
+			return nil, err
+		}
+		wait, ok := c.retry.delay(attempt, retryAfter)
+		if !ok {
+			return nil, err
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, err
+		case <-timer.C:
+		}
+	}
+}
+
+// callAPIOnce makes a single GET request. On failure it also returns how
+// long the server asked us to wait via Retry-After, if it said.
+func (c *Client) callAPIOnce(ctx context.Context, url string) ([]byte, time.Duration, error) {
+	if c.limiter != nil {
+		err := c.limiter.wait(ctx)
+		if err != nil {
+			return nil, 0, fmt.Errorf("waiting for rate limiter: %w", err)
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
-		return nil, &APIError{
-			StatusCode: resp.StatusCode,
-			Err:        fmt.Errorf("can't get %s: %w", url, err),
+		return nil, 0, fmt.Errorf("can't build request for %s: %w", url, err)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, 0, &APIError{
+			Err: fmt.Errorf("can't get %s: %w", url, err),
 		}
 	}
+	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
-		return nil, &APIError{
+		return nil, parseRetryAfter(resp.Header.Get("Retry-After")), &APIError{
 			StatusCode: resp.StatusCode,
 			Err:        fmt.Errorf("call to %s failed: %s", url, resp.Status),
 		}
 	}
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, &APIError{
-			StatusCode: resp.StatusCode,
-			Err:        fmt.Errorf("can't read response body: err"),
+		return nil, 0, &APIError{
+			Err: fmt.Errorf("can't read response body from %s: %w", url, err),
 		}
 	}
-	defer resp.Body.Close()
-	return body, nil
+	return body, 0, nil
 }
 
-func (c *Client) NextLocationAreas() error {
-	var url string
-	if c.config.Next != "" {
-		url = c.config.Next
-	} else {
-		url = fmt.Sprintf("%s/location-area", c.apiUrl)
-	}
-	if body, hit := c.cache.Get(url); hit {
-		err := json.Unmarshal(body, c.config)
+// getJSON unmarshals the JSON at url into v, from the cache if possible.
+func (c *Client) getJSON(ctx context.Context, url string, v any) error {
+	if data, hit := c.cache.Get(url); hit {
+		err := json.Unmarshal(data, v)
 		if err != nil {
-			return fmt.Errorf("can't unmarshal cache result: %w", err)
+			return &DecodeError{URL: url, Err: err}
 		}
 		return nil
 	}
-	data, err := c.callAPI(url)
-	err = json.Unmarshal(data, c.config)
+	data, err := c.callAPI(ctx, url)
+	if err != nil {
+		return err
+	}
+	err = json.Unmarshal(data, v)
 	if err != nil {
-		return fmt.Errorf("can't read response body: %w", err)
+		return &DecodeError{URL: url, Err: err}
 	}
 	c.cache.Add(url, data)
 	return nil
 }
 
-func (c *Client) PreviousLocationAreas() error {
+func (c *Client) NextLocationAreas() error {
+	return c.NextLocationAreasContext(context.Background())
+}
+
+func (c *Client) NextLocationAreasContext(ctx context.Context) error {
 	var url string
-	if c.config.Previous != "" {
-		url = c.config.Previous
+	if c.config.Next != "" {
+		url = c.config.Next
 	} else {
-		return fmt.Errorf("can't go back at beginning of map")
-	}
-	if data, hit := c.cache.Get(url); hit {
-		err := json.Unmarshal(data, c.config)
-		if err != nil {
-			return fmt.Errorf("can't unmarshal cache result: %w", err)
-		}
-		return nil
-	}
-	data, err := c.callAPI(url)
-	if err != nil {
-		return fmt.Errorf("API error: %w", err)
-	}
-	err = json.Unmarshal(data, c.config)
-	if err != nil {
-		return fmt.Errorf("can't unmarshal response body: %w", err)
+		url = fmt.Sprintf("%s/location-area", c.apiUrl)
 	}
-	c.cache.Add(url, data)
-	return nil
+	return c.getJSON(ctx, url, c.config)
 }
 
-func pokemonListFromLocationArea(data []byte) ([]string, error) {
-	var location LocationArea
-	err := json.Unmarshal(data, &location)
-	if err != nil {
-		return nil, fmt.Errorf("can't unmarshal LocationArea data: %w", err)
-	}
-	var pokemonList []string
-	for _, encounter := range location.PokemonEncounters {
-		pokemonList = append(pokemonList, encounter.Pokemon.Name)
+func (c *Client) PreviousLocationAreas() error {
+	return c.PreviousLocationAreasContext(context.Background())
+}
+
+func (c *Client) PreviousLocationAreasContext(ctx context.Context) error {
+	if c.config.Previous == "" {
+		return fmt.Errorf("can't go back at beginning of map")
 	}
-	return pokemonList, nil
+	return c.getJSON(ctx, c.config.Previous, c.config)
 }
 
 func (c *Client) ExploreArea(areaName string) ([]string, error) {
+	return c.ExploreAreaContext(context.Background(), areaName)
+}
+
+func (c *Client) ExploreAreaContext(ctx context.Context, areaName string) ([]string, error) {
+	var location LocationArea
 	url := fmt.Sprintf("%s/location-area/%s", c.apiUrl, areaName)
-	if body, hit := c.cache.Get(url); hit {
-		pokemonList, err := pokemonListFromLocationArea(body)
-		if err != nil {
-			return nil, fmt.Errorf("cache read error: %w", err)
-		}
-		return pokemonList, nil
-	}
-	body, err := c.callAPI(url)
+	err := c.getJSON(ctx, url, &location)
 	if err != nil {
-		return nil, fmt.Errorf("API error: %w", err)
+		return nil, c.withSuggestions(ctx, notFoundAs(err, "area", areaName), "location-area")
 	}
-	pokemonList, err := pokemonListFromLocationArea(body)
-	if err != nil {
-		return nil, fmt.Errorf("can't read location area data: %w", err)
+	c.config.Area = areaName
+	c.EndEncounter()
+	var pokemonList []string
+	for _, encounter := range location.PokemonEncounters {
+		pokemonList = append(pokemonList, encounter.Pokemon.Name)
+		c.markSeen(encounter.Pokemon.Name)
 	}
-	c.cache.Add(url, body)
 	return pokemonList, nil
 }
 
@@ -244,44 +346,49 @@ func (c *Client) GetLocationNames() []string {
 }
 
 func (c *Client) GetPokemon(name string) (Pokemon, error) {
+	return c.GetPokemonContext(context.Background(), name)
+}
+
+func (c *Client) GetPokemonContext(ctx context.Context, name string) (Pokemon, error) {
 	var pokemon Pokemon
 	url := fmt.Sprintf("%s/pokemon/%s", c.apiUrl, name)
-	if data, hit := c.cache.Get(url); hit {
-		err := json.Unmarshal(data, &pokemon)
-		if err != nil {
-			return Pokemon{}, fmt.Errorf("can't unmarshal cache result: %w", err)
-		}
-		return pokemon, nil
-	}
-	body, err := c.callAPI(url)
+	err := c.getJSON(ctx, url, &pokemon)
 	if err != nil {
-		var apiErr *APIError
-		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
-			return Pokemon{}, fmt.Errorf("no such pokemon '%s'", name)
-		}
-		return Pokemon{}, fmt.Errorf("API error: %w", err)
+		return Pokemon{}, c.withSuggestions(ctx, notFoundAs(err, "pokemon", name), "pokemon")
 	}
-	err = json.Unmarshal(body, &pokemon)
-	if err != nil {
-		return Pokemon{}, fmt.Errorf("can't unmarshal pokemon result: %w", err)
-	}
-	c.cache.Add(url, body)
 	return pokemon, nil
 }
 
+// AddPokedexEntry marks p's species as seen and caught.
 func (c *Client) AddPokedexEntry(p Pokemon) {
-	c.config.Pokedex[p.Name] = p
+	c.config.Pokedex[p.Name] = PokedexEntry{
+		Name:    p.Name,
+		Seen:    true,
+		Caught:  true,
+		Pokemon: &p,
+	}
 }
 
-func (c *Client) GetPokedexEntry(name string) (Pokemon, bool) {
+// PokedexEntries returns everything seen or caught, sorted by name.
+func (c *Client) PokedexEntries() []PokedexEntry {
+	entries := slices.Collect(maps.Values(c.config.Pokedex))
+	slices.SortFunc(entries, func(a, b PokedexEntry) int { return strings.Compare(a.Name, b.Name) })
+	return entries
+}
+
+func (c *Client) GetPokedexEntry(name string) (PokedexEntry, bool) {
 	val, ok := c.config.Pokedex[name]
 	return val, ok
 }
 
+// ListPokedex returns the names of every species caught, sorted.
 func (c *Client) ListPokedex() []string {
 	var names []string
-	for _, pokemon := range c.config.Pokedex {
-		names = append(names, pokemon.Name)
+	for _, entry := range c.config.Pokedex {
+		if entry.Caught {
+			names = append(names, entry.Name)
+		}
 	}
+	slices.Sort(names)
 	return names
 }
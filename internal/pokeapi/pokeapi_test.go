@@ -0,0 +1,439 @@
+package pokeapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/tquid/pokedexcli/internal/fakepokeapi"
+	"github.com/tquid/pokedexcli/internal/pokecache"
+)
+
+func TestContextCancelsRequest(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	c, err := NewClient(
+		WithBaseURL(server.URL),
+		WithSavePath(filepath.Join(t.TempDir(), "save.json")),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = c.GetPokemonContext(ctx, "pikachu")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+}
+
+// waitForGoroutines fails unless the goroutine count drops to want within
+// a second.
+func waitForGoroutines(t *testing.T, want int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > want {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected at most %d goroutines, have %d", want, runtime.NumGoroutine())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestCloseStopsCacheReaper(t *testing.T) {
+	before := runtime.NumGoroutine()
+	c, err := NewClient(WithSavePath(filepath.Join(t.TempDir(), "save.json")))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if runtime.NumGoroutine() <= before {
+		t.Fatalf("expected the client's cache to start a reaper")
+	}
+	c.Close()
+	waitForGoroutines(t, before)
+
+	// A cache passed in belongs to the caller, so Close leaves it running.
+	cache := pokecache.NewCache(time.Minute)
+	defer cache.Close()
+	withCache := runtime.NumGoroutine()
+	c, err = NewClient(WithCache(cache), WithSavePath(filepath.Join(t.TempDir(), "save.json")))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	c.Close()
+	waitForGoroutines(t, withCache)
+	if runtime.NumGoroutine() < withCache {
+		t.Errorf("expected Close to leave the caller's cache reaper running")
+	}
+}
+
+func TestRetry(t *testing.T) {
+	fastRetry := RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+	cases := []struct {
+		name         string
+		statuses     []int
+		wantErr      bool
+		wantRequests int
+	}{
+		{name: "succeeds after transient errors", statuses: []int{503, 429, 200}, wantRequests: 3},
+		{name: "gives up after max attempts", statuses: []int{500, 502, 504}, wantErr: true, wantRequests: 3},
+		{name: "does not retry not found", statuses: []int{404}, wantErr: true, wantRequests: 1},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			requests := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				// A 404 also fetches the name list for suggestions, which
+				// isn't a retry.
+				if r.URL.Query().Has("limit") {
+					w.Write([]byte(`{"results": []}`))
+					return
+				}
+				status := tc.statuses[requests]
+				requests++
+				if status == http.StatusTooManyRequests {
+					w.Header().Set("Retry-After", "0")
+				}
+				w.WriteHeader(status)
+				w.Write([]byte(`{"name": "pikachu"}`))
+			}))
+			defer server.Close()
+
+			c, err := NewClient(
+				WithBaseURL(server.URL),
+				WithRetry(fastRetry),
+				WithSavePath(filepath.Join(t.TempDir(), "save.json")),
+			)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			defer c.Close()
+
+			_, err = c.GetPokemon("pikachu")
+			if (err != nil) != tc.wantErr {
+				t.Errorf("expected error=%v, got %v", tc.wantErr, err)
+			}
+			if requests != tc.wantRequests {
+				t.Errorf("expected %d requests, got %d", tc.wantRequests, requests)
+			}
+			var apiErr *APIError
+			if tc.wantErr && errors.As(err, &apiErr) && apiErr.Attempts != tc.wantRequests {
+				t.Errorf("expected error to report %d attempts, got %d", tc.wantRequests, apiErr.Attempts)
+			}
+		})
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	cases := []struct {
+		name         string
+		retryAfter   string
+		maxDelay     time.Duration
+		wantErr      bool
+		wantRequests int
+		minElapsed   time.Duration
+	}{
+		{name: "waits as long as asked", retryAfter: "1", maxDelay: 5 * time.Second, wantRequests: 2, minElapsed: time.Second},
+		{name: "gives up if asked to wait too long", retryAfter: "60", maxDelay: 5 * time.Second, wantErr: true, wantRequests: 1},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			requests := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				if requests == 1 {
+					w.Header().Set("Retry-After", tc.retryAfter)
+					w.WriteHeader(http.StatusTooManyRequests)
+					return
+				}
+				w.Write([]byte(`{"name": "pikachu"}`))
+			}))
+			defer server.Close()
+
+			c, err := NewClient(
+				WithBaseURL(server.URL),
+				WithRetry(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: tc.maxDelay}),
+				WithSavePath(filepath.Join(t.TempDir(), "save.json")),
+			)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			defer c.Close()
+
+			start := time.Now()
+			_, err = c.GetPokemon("pikachu")
+			elapsed := time.Since(start)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("expected error=%v, got %v", tc.wantErr, err)
+			}
+			if requests != tc.wantRequests {
+				t.Errorf("expected %d requests, got %d", tc.wantRequests, requests)
+			}
+			if elapsed < tc.minElapsed {
+				t.Errorf("expected to wait at least %v, took %v", tc.minElapsed, elapsed)
+			}
+			var apiErr *APIError
+			if tc.wantErr && (!errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusTooManyRequests || apiErr.Attempts != 1) {
+				t.Errorf("expected a 429 error after 1 attempt, got %v", err)
+			}
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	cases := []struct {
+		header string
+		want   time.Duration
+	}{
+		{header: "", want: 0},
+		{header: "3", want: 3 * time.Second},
+		{header: "-1", want: 0},
+		{header: "soon", want: 0},
+		{header: "Mon, 02 Jan 2006 15:04:05 GMT", want: 0},
+	}
+	for _, c := range cases {
+		got := parseRetryAfter(c.header)
+		if got != c.want {
+			t.Errorf("parseRetryAfter(%q): expected %v, got %v", c.header, c.want, got)
+		}
+	}
+}
+
+func TestConcurrentRequestsCoalesce(t *testing.T) {
+	const callers = 10
+	var requests atomic.Int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		<-release
+		w.Write([]byte(`{"name": "pikachu"}`))
+	}))
+	defer server.Close()
+
+	c, err := NewClient(
+		WithBaseURL(server.URL),
+		WithRateLimit(0, 0),
+		WithSavePath(filepath.Join(t.TempDir(), "save.json")),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer c.Close()
+
+	var wg sync.WaitGroup
+	for range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			pokemon, err := c.GetPokemon("pikachu")
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+			if pokemon.Name != "pikachu" {
+				t.Errorf("expected pikachu, got %s", pokemon.Name)
+			}
+		}()
+	}
+	// Give every caller time to miss the cache before the server answers.
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if got := requests.Load(); got != 1 {
+		t.Errorf("expected 1 request, got %d", got)
+	}
+}
+
+// newFixtureClient returns a client that serves every request from the
+// fixtures in testdata/fixtures, trimmed copies of real PokeAPI responses.
+func newFixtureClient(t *testing.T, opts ...Option) *Client {
+	t.Helper()
+	opts = append([]Option{
+		WithHTTPClient(&http.Client{Transport: NewReplayTransport("testdata/fixtures")}),
+		WithRetry(NoRetry),
+		WithRateLimit(0, 0),
+		WithSavePath(filepath.Join(t.TempDir(), "save.json")),
+	}, opts...)
+	c, err := NewClient(opts...)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Cleanup(c.Close)
+	return c
+}
+
+func TestLocationAreaPaging(t *testing.T) {
+	c := newFixtureClient(t)
+	if !c.IsNew() {
+		t.Errorf("expected new client before first page")
+	}
+
+	err := c.PreviousLocationAreas()
+	if err == nil {
+		t.Errorf("expected error going back from the start")
+	}
+
+	steps := []struct {
+		name  string
+		move  func() error
+		first string
+	}{
+		{name: "first page", move: c.NextLocationAreas, first: "canalave-city-area"},
+		{name: "second page", move: c.NextLocationAreas, first: "mt-coronet-1f-route-216"},
+		{name: "back to first page", move: c.PreviousLocationAreas, first: "canalave-city-area"},
+		{name: "second page from cache", move: c.NextLocationAreas, first: "mt-coronet-1f-route-216"},
+	}
+	for _, step := range steps {
+		err := step.move()
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", step.name, err)
+		}
+		names := c.GetLocationNames()
+		if len(names) != 20 {
+			t.Errorf("%s: expected 20 names, got %d", step.name, len(names))
+			continue
+		}
+		if names[0] != step.first {
+			t.Errorf("%s: expected first name %s, got %s", step.name, step.first, names[0])
+		}
+	}
+	if c.IsNew() {
+		t.Errorf("expected client not to be new after paging")
+	}
+}
+
+func TestExploreArea(t *testing.T) {
+	c := newFixtureClient(t)
+	cases := []struct {
+		area    string
+		want    []string
+		wantErr bool
+	}{
+		{area: "pastoria-city-area", want: []string{"tentacool", "tentacruel", "magikarp", "psyduck", "buizel"}},
+		{area: "no-such-area", wantErr: true},
+	}
+	for _, tc := range cases {
+		got, err := c.ExploreArea(tc.area)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("%s: expected error=%v, got %v", tc.area, tc.wantErr, err)
+			continue
+		}
+		if !slices.Equal(got, tc.want) {
+			t.Errorf("%s: expected %v, got %v", tc.area, tc.want, got)
+		}
+	}
+}
+
+func TestGetPokemon(t *testing.T) {
+	c := newFixtureClient(t)
+	cases := []struct {
+		name     string
+		wantExp  int
+		wantType string
+		wantErr  bool
+	}{
+		{name: "pikachu", wantExp: 112, wantType: "electric"},
+		{name: "tentacool", wantExp: 67, wantType: "water"},
+		{name: "missingno", wantErr: true},
+	}
+	for _, tc := range cases {
+		pokemon, err := c.GetPokemon(tc.name)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("%s: expected error=%v, got %v", tc.name, tc.wantErr, err)
+			continue
+		}
+		if tc.wantErr {
+			continue
+		}
+		if pokemon.BaseExperience != tc.wantExp {
+			t.Errorf("%s: expected base experience %d, got %d", tc.name, tc.wantExp, pokemon.BaseExperience)
+		}
+		if len(pokemon.Types) == 0 || pokemon.Types[0].Type.Name != tc.wantType {
+			t.Errorf("%s: expected first type %s", tc.name, tc.wantType)
+		}
+	}
+}
+
+func TestPokedexEntries(t *testing.T) {
+	c := newFixtureClient(t)
+	if len(c.ListPokedex()) != 0 {
+		t.Errorf("expected empty pokedex")
+	}
+	for _, name := range []string{"ditto", "magikarp"} {
+		pokemon, err := c.GetPokemon(name)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		c.AddPokedexEntry(pokemon)
+	}
+
+	names := c.ListPokedex()
+	if !slices.Equal(names, []string{"ditto", "magikarp"}) {
+		t.Errorf("expected ditto and magikarp, got %v", names)
+	}
+	entry, ok := c.GetPokedexEntry("magikarp")
+	if !ok || !entry.Caught || entry.Pokemon == nil || entry.Pokemon.Weight != 100 {
+		t.Errorf("expected to find magikarp with weight 100")
+	}
+	if _, ok := c.GetPokedexEntry("pikachu"); ok {
+		t.Errorf("expected not to find uncaught pikachu")
+	}
+}
+
+func TestAgainstFakeServer(t *testing.T) {
+	server, err := fakepokeapi.NewTestServer()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer server.Close()
+
+	c, err := NewClient(
+		WithBaseURL(server.URL+"/api/v2"),
+		WithRetry(NoRetry),
+		WithRateLimit(0, 0),
+		WithSavePath(filepath.Join(t.TempDir(), "save.json")),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer c.Close()
+
+	err = c.NextLocationAreas()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	names := c.GetLocationNames()
+	if len(names) == 0 {
+		t.Fatalf("expected location names")
+	}
+	pokemonList, err := c.ExploreArea(names[0])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(pokemonList) == 0 {
+		t.Errorf("expected pokemon in %s", names[0])
+	}
+	_, err = c.GetPokemon("missingno")
+	if err == nil {
+		t.Errorf("expected error for unknown pokemon")
+	}
+}
@@ -1,9 +1,5 @@
 package pokeapi
 
-import (
-	"math/rand"
-)
-
 type Pokemon struct {
 	ID             int    `json:"id"`
 	Name           string `json:"name"`
@@ -275,20 +271,3 @@ type Pokemon struct {
 		} `json:"types"`
 	} `json:"past_types"`
 }
-
-// The real formula is very complex. This just takes BaseExperience, which
-// varies between about 60 - 200, and calculates an integer chance meant to
-// be a percentage
-func (p Pokemon) captureChance() int {
-	chance := 100 - p.BaseExperience/2
-	if chance <= 0 {
-		chance = 1
-	}
-	return chance
-}
-
-func (p Pokemon) Catch() bool {
-	chance := p.captureChance()
-	roll := rand.Intn(100) + 1
-	return roll <= chance
-}
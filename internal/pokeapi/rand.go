@@ -0,0 +1,35 @@
+package pokeapi
+
+import (
+	"math/rand"
+	"time"
+)
+
+// Rand is the source of every random choice in play: which Pokemon shows
+// up, at what level, and whether a ball holds. *rand.Rand satisfies it.
+type Rand interface {
+	// Intn returns a number in [0, n).
+	Intn(n int) int
+}
+
+func newSeededRand(seed int64) Rand {
+	return rand.New(rand.NewSource(seed))
+}
+
+// newSeed picks a seed for a client that wasn't given one.
+func newSeed() int64 {
+	return time.Now().UnixNano()
+}
+
+// Seed returns the seed the random source was last started from, so a
+// session can be replayed with WithSeed or SetSeed. It means nothing if
+// WithRand was used.
+func (c *Client) Seed() int64 {
+	return c.seed
+}
+
+// SetSeed restarts the random source from seed.
+func (c *Client) SetSeed(seed int64) {
+	c.seed = seed
+	c.rand = newSeededRand(seed)
+}
@@ -0,0 +1,80 @@
+package pokeapi
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+// playSession explores, rolls encounters and throws balls, and returns
+// everything that happened.
+func playSession(t *testing.T, c *Client) []string {
+	t.Helper()
+	_, err := c.ExploreArea("pastoria-city-area")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	c.config.Bag["poke-ball"] = 100
+	var log []string
+	for range 10 {
+		wild, err := c.Encounter("old-rod", "")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		result, err := c.CatchWild(PokeBall)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		log = append(log, fmt.Sprintf("%s lv%d caught=%v shakes=%d", wild.Pokemon, wild.Level, result.Caught, result.Shakes))
+	}
+	return log
+}
+
+func TestSeedReplays(t *testing.T) {
+	first := newFixtureClient(t, WithSeed(42))
+	second := newFixtureClient(t, WithSeed(42))
+	if first.Seed() != 42 {
+		t.Errorf("expected seed 42, got %d", first.Seed())
+	}
+	a, b := playSession(t, first), playSession(t, second)
+	if !slices.Equal(a, b) {
+		t.Errorf("expected the same seed to replay the same session:\n%v\n%v", a, b)
+	}
+
+	other := newFixtureClient(t, WithSeed(7))
+	if slices.Equal(a, playSession(t, other)) {
+		t.Errorf("expected a different seed to play differently")
+	}
+}
+
+func TestSetSeed(t *testing.T) {
+	c := newFixtureClient(t)
+	c.SetSeed(42)
+	a := playSession(t, c)
+	c.SetSeed(42)
+	b := playSession(t, c)
+	if !slices.Equal(a, b) {
+		t.Errorf("expected reseeding to replay the session:\n%v\n%v", a, b)
+	}
+}
+
+// zeroRand always rolls the lowest number.
+type zeroRand struct{}
+
+func (zeroRand) Intn(int) int { return 0 }
+
+func TestWithRand(t *testing.T) {
+	c := newFixtureClient(t, WithRand(zeroRand{}))
+	_, err := c.ExploreArea("eterna-forest-area")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wild, err := c.Encounter("walk", "diamond")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// The first slot listed for diamond is bidoof at levels 10-12.
+	if wild.Pokemon != "bidoof" || wild.Level != 10 {
+		t.Errorf("expected bidoof at level 10, got %s at %d", wild.Pokemon, wild.Level)
+	}
+}
@@ -0,0 +1,46 @@
+package pokeapi
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterSharedAcrossGoroutines(t *testing.T) {
+	const rate = 200
+	const callers = 10
+	limiter := newRateLimiter(rate, 1)
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := limiter.wait(context.Background())
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// The first call uses the burst token; the rest wait 1/rate each.
+	want := time.Duration(callers-1) * time.Second / rate
+	if elapsed := time.Since(start); elapsed < want-time.Millisecond {
+		t.Errorf("expected at least %v for %d calls, took %v", want, callers, elapsed)
+	}
+}
+
+func TestRateLimiterCancel(t *testing.T) {
+	limiter := newRateLimiter(1, 1)
+	limiter.wait(context.Background())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	err := limiter.wait(ctx)
+	if err == nil {
+		t.Errorf("expected error waiting with cancelled context")
+	}
+}
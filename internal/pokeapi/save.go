@@ -0,0 +1,188 @@
+package pokeapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// Bump saveVersion whenever saveFile changes shape, and teach migrateSave
+// how to bring the previous version forward.
+const saveVersion = 3
+
+type saveFile struct {
+	Version     int            `json:"version"`
+	Next        string         `json:"next"`
+	Previous    string         `json:"previous"`
+	Area        string         `json:"area"`
+	Pokedex     Pokedex        `json:"pokedex"`
+	Box         []OwnedPokemon `json:"box"`
+	LastOwnedID int            `json:"last_owned_id"`
+	Bag         Bag            `json:"bag"`
+}
+
+// Version 1 kept one Pokemon per species, keyed by name.
+type saveFileV1 struct {
+	Next     string             `json:"next"`
+	Previous string             `json:"previous"`
+	Pokedex  map[string]Pokemon `json:"pokedex"`
+}
+
+// v1CatchLevel is the level given to Pokemon caught before levels existed.
+const v1CatchLevel = 5
+
+func defaultSavePath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("can't find user config dir: %w", err)
+	}
+	return filepath.Join(dir, "pokedexcli", "save.json"), nil
+}
+
+func migrateSave(data []byte) (saveFile, error) {
+	var s saveFile
+	err := json.Unmarshal(data, &s)
+	if err != nil {
+		return saveFile{}, err
+	}
+	switch {
+	case s.Version > saveVersion:
+		return saveFile{}, fmt.Errorf("save file version %d is newer than supported version %d", s.Version, saveVersion)
+	case s.Version < 1:
+		return saveFile{}, fmt.Errorf("save file has invalid version %d", s.Version)
+	case s.Version == 1:
+		s, err = migrateV1(data)
+		if err != nil {
+			return saveFile{}, err
+		}
+	}
+	// Version 2 had no bag.
+	if s.Version < 3 {
+		s.Bag = startingBag()
+	}
+	s.Version = saveVersion
+	if s.Pokedex == nil {
+		s.Pokedex = make(Pokedex)
+	}
+	if s.Bag == nil {
+		s.Bag = make(Bag)
+	}
+	return s, nil
+}
+
+// migrateV1 turns each species in a version 1 Pokedex into a caught entry
+// plus one owned Pokemon. When and where it was caught wasn't recorded.
+func migrateV1(data []byte) (saveFile, error) {
+	var old saveFileV1
+	err := json.Unmarshal(data, &old)
+	if err != nil {
+		return saveFile{}, err
+	}
+	s := saveFile{
+		Version:  2,
+		Next:     old.Next,
+		Previous: old.Previous,
+		Pokedex:  make(Pokedex),
+	}
+	for _, name := range slices.Sorted(maps.Keys(old.Pokedex)) {
+		p := old.Pokedex[name]
+		s.Pokedex[p.Name] = PokedexEntry{Name: p.Name, Seen: true, Caught: true, Pokemon: &p}
+		s.LastOwnedID++
+		s.Box = append(s.Box, OwnedPokemon{
+			ID:      s.LastOwnedID,
+			Species: p.Name,
+			Level:   v1CatchLevel,
+			Ball:    "poke-ball",
+		})
+	}
+	return s, nil
+}
+
+func (c *Client) SavePath() string {
+	return c.savePath
+}
+
+func (c *Client) Save() error {
+	return c.SaveTo(c.savePath)
+}
+
+func (c *Client) SaveTo(path string) error {
+	if path == "" {
+		return fmt.Errorf("no save file path configured")
+	}
+	s := saveFile{
+		Version:     saveVersion,
+		Next:        c.config.Next,
+		Previous:    c.config.Previous,
+		Area:        c.config.Area,
+		Pokedex:     c.config.Pokedex,
+		Box:         c.config.Box,
+		LastOwnedID: c.config.LastOwnedID,
+		Bag:         c.config.Bag,
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("can't marshal save data: %w", err)
+	}
+	err = os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		return fmt.Errorf("can't create save directory: %w", err)
+	}
+	// Write to a temp file and rename so a crash mid-write can't truncate
+	// an existing save.
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".save-*.json")
+	if err != nil {
+		return fmt.Errorf("can't create temp save file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.Write(data)
+	if err != nil {
+		tmp.Close()
+		return fmt.Errorf("can't write save file: %w", err)
+	}
+	err = tmp.Close()
+	if err != nil {
+		return fmt.Errorf("can't write save file: %w", err)
+	}
+	err = os.Rename(tmp.Name(), path)
+	if err != nil {
+		return fmt.Errorf("can't replace save file %s: %w", path, err)
+	}
+	return nil
+}
+
+func (c *Client) LoadFrom(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("can't read save file: %w", err)
+	}
+	s, err := migrateSave(data)
+	if err != nil {
+		return fmt.Errorf("can't load save file %s: %w", path, err)
+	}
+	c.config.Next = s.Next
+	c.config.Previous = s.Previous
+	c.config.Results = nil
+	c.config.Area = s.Area
+	c.EndEncounter()
+	c.config.Pokedex = s.Pokedex
+	c.config.Box = s.Box
+	c.config.LastOwnedID = s.LastOwnedID
+	c.config.Bag = s.Bag
+	return nil
+}
+
+// loadSave loads the default save file, treating a missing file as a fresh
+// start.
+func (c *Client) loadSave() error {
+	err := c.LoadFrom(c.savePath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
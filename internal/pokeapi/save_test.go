@@ -0,0 +1,103 @@
+package pokeapi
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSaveLoad(t *testing.T) {
+	savePath := filepath.Join(t.TempDir(), "save.json")
+	c, err := NewClient(WithSavePath(savePath))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer c.Close()
+	c.config.Next = "https://example.com/next"
+	c.config.Previous = "https://example.com/previous"
+	c.AddToBox(Pokemon{Name: "pikachu", Height: 4}, 5, "poke-ball")
+	c.config.Bag["poke-ball"] = 3
+	err = c.Save()
+	if err != nil {
+		t.Fatalf("unexpected error saving: %v", err)
+	}
+
+	loaded, err := NewClient(WithSavePath(savePath))
+	if err != nil {
+		t.Fatalf("unexpected error loading: %v", err)
+	}
+	defer loaded.Close()
+	entry, ok := loaded.GetPokedexEntry("pikachu")
+	if !ok || entry.Pokemon == nil {
+		t.Fatalf("expected pikachu in loaded pokedex")
+	}
+	if entry.Pokemon.Height != 4 {
+		t.Errorf("expected height 4, got %d", entry.Pokemon.Height)
+	}
+	if box := loaded.Box(); len(box) != 1 || box[0].Species != "pikachu" {
+		t.Errorf("expected pikachu in loaded box, got %+v", box)
+	}
+	if got := loaded.Bag()["poke-ball"]; got != 3 {
+		t.Errorf("expected 3 poke balls in loaded bag, got %d", got)
+	}
+	if loaded.config.Next != c.config.Next || loaded.config.Previous != c.config.Previous {
+		t.Errorf("expected map cursor to be restored")
+	}
+}
+
+func TestLoadRejectsNewerVersion(t *testing.T) {
+	c, err := NewClient(WithSavePath(filepath.Join(t.TempDir(), "save.json")))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer c.Close()
+	path := filepath.Join(t.TempDir(), "save.json")
+	err = os.WriteFile(path, []byte(`{"version": 999}`), 0o644)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err = c.LoadFrom(path)
+	if err == nil {
+		t.Errorf("expected error loading newer save version")
+	}
+}
+
+func TestLoadMigratesVersion1(t *testing.T) {
+	c, err := NewClient(WithSavePath(filepath.Join(t.TempDir(), "save.json")))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer c.Close()
+	path := filepath.Join(t.TempDir(), "save.json")
+	v1 := `{"version": 1, "next": "", "previous": "", "pokedex": {
+		"pikachu": {"name": "pikachu", "height": 4},
+		"ditto": {"name": "ditto", "height": 3}
+	}}`
+	err = os.WriteFile(path, []byte(v1), 0o644)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err = c.LoadFrom(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	entry, ok := c.GetPokedexEntry("pikachu")
+	if !ok || !entry.Caught || entry.Pokemon == nil || entry.Pokemon.Height != 4 {
+		t.Errorf("expected caught pikachu with height 4, got %+v", entry)
+	}
+	box := c.Box()
+	if len(box) != 2 {
+		t.Fatalf("expected 2 pokemon in box, got %d", len(box))
+	}
+	if box[0].ID == box[1].ID {
+		t.Errorf("expected unique IDs after migration")
+	}
+	if c.Bag()["poke-ball"] == 0 {
+		t.Errorf("expected a migrated save to get a starting bag")
+	}
+	// New catches must not reuse a migrated ID.
+	owned := c.AddToBox(Pokemon{Name: "magikarp"}, 5, "poke-ball")
+	if owned.ID <= box[1].ID {
+		t.Errorf("expected new ID after %d, got %d", box[1].ID, owned.ID)
+	}
+}
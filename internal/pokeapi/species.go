@@ -0,0 +1,52 @@
+package pokeapi
+
+import (
+	"context"
+	"fmt"
+)
+
+// Species holds what's shared by every form of a Pokemon, most usefully
+// how hard it is to catch.
+type Species struct {
+	ID            int    `json:"id"`
+	Name          string `json:"name"`
+	CaptureRate   int    `json:"capture_rate"`
+	BaseHappiness int    `json:"base_happiness"`
+	GenderRate    int    `json:"gender_rate"`
+	IsBaby        bool   `json:"is_baby"`
+	IsLegendary   bool   `json:"is_legendary"`
+	IsMythical    bool   `json:"is_mythical"`
+	GrowthRate    struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	} `json:"growth_rate"`
+	Generation struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	} `json:"generation"`
+	EvolvesFromSpecies *struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	} `json:"evolves_from_species"`
+	Genera []struct {
+		Genus    string `json:"genus"`
+		Language struct {
+			Name string `json:"name"`
+			URL  string `json:"url"`
+		} `json:"language"`
+	} `json:"genera"`
+}
+
+func (c *Client) GetSpecies(name string) (Species, error) {
+	return c.GetSpeciesContext(context.Background(), name)
+}
+
+func (c *Client) GetSpeciesContext(ctx context.Context, name string) (Species, error) {
+	var species Species
+	url := fmt.Sprintf("%s/pokemon-species/%s", c.apiUrl, name)
+	err := c.getJSON(ctx, url, &species)
+	if err != nil {
+		return Species{}, notFoundAs(err, "species", name)
+	}
+	return species, nil
+}
@@ -0,0 +1,110 @@
+package pokeapi
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Large enough that one request lists every resource of a kind.
+const nameIndexLimit = 100000
+
+const maxSuggestions = 3
+
+type resourceList struct {
+	Count   int `json:"count"`
+	Results []struct {
+		Name string `json:"name"`
+	} `json:"results"`
+}
+
+// nameIndex fetches every name PokeAPI has for a resource kind, e.g.
+// "pokemon" or "location-area". The listing is cached like any other
+// response.
+func (c *Client) nameIndex(ctx context.Context, kind string) ([]string, error) {
+	var list resourceList
+	url := fmt.Sprintf("%s/%s?limit=%d", c.apiUrl, kind, nameIndexLimit)
+	err := c.getJSON(ctx, url, &list)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(list.Results))
+	for _, result := range list.Results {
+		names = append(names, result.Name)
+	}
+	return names, nil
+}
+
+// withSuggestions fills in "did you mean" names on a NotFoundError. Failing
+// to build the index isn't worth reporting; the user still learns the name
+// was wrong.
+func (c *Client) withSuggestions(ctx context.Context, err error, kind string) error {
+	notFound, ok := err.(*NotFoundError)
+	if !ok {
+		return err
+	}
+	names, indexErr := c.nameIndex(ctx, kind)
+	if indexErr == nil {
+		notFound.Suggestions = suggest(notFound.Name, names)
+	}
+	return notFound
+}
+
+// suggest returns up to maxSuggestions names close to query, best first.
+// Names within a small edit distance count, as do names that contain the
+// query, so "canalave-city" finds "canalave-city-area".
+func suggest(query string, names []string) []string {
+	type match struct {
+		name  string
+		score int
+	}
+	threshold := max(1, len(query)/3)
+	var matches []match
+	for _, name := range names {
+		if name == query {
+			continue
+		}
+		score := levenshtein(query, name)
+		if len(query) >= 3 && strings.Contains(name, query) {
+			score = min(score, threshold)
+		}
+		if score <= threshold {
+			matches = append(matches, match{name: name, score: score})
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score < matches[j].score
+		}
+		return matches[i].name < matches[j].name
+	})
+	var suggestions []string
+	for i := 0; i < len(matches) && i < maxSuggestions; i++ {
+		suggestions = append(suggestions, matches[i].name)
+	}
+	return suggestions
+}
+
+// levenshtein returns the number of single character insertions, deletions
+// and substitutions needed to turn a into b.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
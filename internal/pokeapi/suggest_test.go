@@ -0,0 +1,77 @@
+package pokeapi
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestLevenshtein(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{a: "", b: "", want: 0},
+		{a: "pikachu", b: "pikachu", want: 0},
+		{a: "pikachuu", b: "pikachu", want: 1},
+		{a: "pikahcu", b: "pikachu", want: 2},
+		{a: "kitten", b: "sitting", want: 3},
+		{a: "", b: "abc", want: 3},
+	}
+	for _, c := range cases {
+		got := levenshtein(c.a, c.b)
+		if got != c.want {
+			t.Errorf("levenshtein(%q, %q): expected %d, got %d", c.a, c.b, c.want, got)
+		}
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	names := []string{"pikachu", "raichu", "pichu", "canalave-city-area", "eterna-city-area", "magikarp"}
+	cases := []struct {
+		query string
+		want  []string
+	}{
+		{query: "pikachuu", want: []string{"pikachu"}},
+		{query: "canalave-city", want: []string{"canalave-city-area"}},
+		{query: "magicarp", want: []string{"magikarp"}},
+		{query: "zzzzzz", want: nil},
+	}
+	for _, c := range cases {
+		got := suggest(c.query, names)
+		if !slices.Equal(got, c.want) {
+			t.Errorf("suggest(%q): expected %v, got %v", c.query, c.want, got)
+		}
+	}
+}
+
+func TestNotFoundSuggestions(t *testing.T) {
+	c := newFixtureClient(t)
+	cases := []struct {
+		name   string
+		lookup func() error
+		want   string
+	}{
+		{
+			name:   "pokemon",
+			lookup: func() error { _, err := c.GetPokemon("pikachuu"); return err },
+			want:   "pikachu",
+		},
+		{
+			name:   "area",
+			lookup: func() error { _, err := c.ExploreArea("canalave-city"); return err },
+			want:   "canalave-city-area",
+		},
+	}
+	for _, tc := range cases {
+		var notFound *NotFoundError
+		err := tc.lookup()
+		if !errors.As(err, &notFound) {
+			t.Errorf("%s: expected NotFoundError, got %v", tc.name, err)
+			continue
+		}
+		if len(notFound.Suggestions) == 0 || notFound.Suggestions[0] != tc.want {
+			t.Errorf("%s: expected first suggestion %s, got %v", tc.name, tc.want, notFound.Suggestions)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package pokeapi
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// typeRef is how PokeAPI links to a type.
+type typeRef = struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// Type is a row of the type chart, from PokeAPI's /type resource. The "to"
+// relations are how its moves fare against other types, the "from" ones how
+// other types' moves fare against Pokemon of this type.
+type Type struct {
+	ID              int    `json:"id"`
+	Name            string `json:"name"`
+	DamageRelations struct {
+		DoubleDamageTo   []typeRef `json:"double_damage_to"`
+		HalfDamageTo     []typeRef `json:"half_damage_to"`
+		NoDamageTo       []typeRef `json:"no_damage_to"`
+		DoubleDamageFrom []typeRef `json:"double_damage_from"`
+		HalfDamageFrom   []typeRef `json:"half_damage_from"`
+		NoDamageFrom     []typeRef `json:"no_damage_from"`
+	} `json:"damage_relations"`
+}
+
+func (c *Client) GetType(name string) (Type, error) {
+	return c.GetTypeContext(context.Background(), name)
+}
+
+func (c *Client) GetTypeContext(ctx context.Context, name string) (Type, error) {
+	var t Type
+	url := fmt.Sprintf("%s/type/%s", c.apiUrl, name)
+	err := c.getJSON(ctx, url, &t)
+	if err != nil {
+		return Type{}, notFoundAs(err, "type", name)
+	}
+	return t, nil
+}
+
+func (c *Client) Effectiveness(attackType string, defenderTypes []string) (float64, error) {
+	return c.EffectivenessContext(context.Background(), attackType, defenderTypes)
+}
+
+// EffectivenessContext returns what a move of attackType multiplies its
+// damage by against a Pokemon with defenderTypes: 0, 0.25, 0.5, 1, 2 or 4.
+func (c *Client) EffectivenessContext(ctx context.Context, attackType string, defenderTypes []string) (float64, error) {
+	t, err := c.GetTypeContext(ctx, attackType)
+	if err != nil {
+		return 0, err
+	}
+	return effectiveness(t, defenderTypes), nil
+}
+
+func effectiveness(attack Type, defenderTypes []string) float64 {
+	relations := attack.DamageRelations
+	multiplier := 1.0
+	for _, defender := range defenderTypes {
+		multiplier *= relationMultiplier(defender, relations.DoubleDamageTo, relations.HalfDamageTo, relations.NoDamageTo)
+	}
+	return multiplier
+}
+
+func relationMultiplier(name string, double, half, none []typeRef) float64 {
+	is := func(r typeRef) bool { return r.Name == name }
+	switch {
+	case slices.ContainsFunc(none, is):
+		return 0
+	case slices.ContainsFunc(double, is):
+		return 2
+	case slices.ContainsFunc(half, is):
+		return 0.5
+	}
+	return 1
+}
+
+func (c *Client) DamageTaken(defenderTypes []string) (map[string]float64, error) {
+	return c.DamageTakenContext(context.Background(), defenderTypes)
+}
+
+// DamageTakenContext returns the multiplier for every attacking type that
+// isn't neutral against a Pokemon with defenderTypes, e.g. {"ground": 2,
+// "flying": 0.5} for an electric type.
+func (c *Client) DamageTakenContext(ctx context.Context, defenderTypes []string) (map[string]float64, error) {
+	taken := make(map[string]float64)
+	for _, name := range defenderTypes {
+		t, err := c.GetTypeContext(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		relations := t.DamageRelations
+		for _, list := range [][]typeRef{relations.DoubleDamageFrom, relations.HalfDamageFrom, relations.NoDamageFrom} {
+			for _, attack := range list {
+				if _, ok := taken[attack.Name]; !ok {
+					taken[attack.Name] = 1
+				}
+				taken[attack.Name] *= relationMultiplier(attack.Name, relations.DoubleDamageFrom, relations.HalfDamageFrom, relations.NoDamageFrom)
+			}
+		}
+	}
+	// A weakness and a resistance cancel out, e.g. grass against water/poison.
+	maps.DeleteFunc(taken, func(_ string, m float64) bool { return m == 1 })
+	return taken, nil
+}
@@ -0,0 +1,83 @@
+package pokeapi
+
+import (
+	"maps"
+	"sync"
+	"testing"
+)
+
+func TestEffectiveness(t *testing.T) {
+	c := newFixtureClient(t)
+	cases := []struct {
+		attack   string
+		defender []string
+		want     float64
+	}{
+		{"electric", []string{"water"}, 2},
+		{"electric", []string{"water", "flying"}, 4},
+		{"electric", []string{"ground"}, 0},
+		{"water", []string{"water", "poison"}, 0.5},
+		{"grass", []string{"water", "poison"}, 1},
+		{"fire", []string{"water", "rock"}, 0.25},
+		{"normal", []string{"electric"}, 1},
+	}
+	for _, tc := range cases {
+		got, err := c.Effectiveness(tc.attack, tc.defender)
+		if err != nil {
+			t.Errorf("%s against %v: unexpected error: %v", tc.attack, tc.defender, err)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("%s against %v: expected %v, got %v", tc.attack, tc.defender, tc.want, got)
+		}
+	}
+	if _, err := c.Effectiveness("cosmic", []string{"water"}); err == nil {
+		t.Errorf("expected error for an unknown type")
+	}
+}
+
+func TestDamageTaken(t *testing.T) {
+	c := newFixtureClient(t)
+	cases := []struct {
+		defender []string
+		want     map[string]float64
+	}{
+		{[]string{"electric"}, map[string]float64{"ground": 2, "flying": 0.5, "steel": 0.5, "electric": 0.5}},
+		// Tentacool: grass is both a weakness and a resistance, so it's gone.
+		{[]string{"water", "poison"}, map[string]float64{
+			"electric": 2, "ground": 2, "psychic": 2,
+			"fighting": 0.5, "poison": 0.5, "bug": 0.5, "steel": 0.5,
+			"fire": 0.5, "water": 0.5, "ice": 0.5, "fairy": 0.5,
+		}},
+		{[]string{"normal", "flying"}, map[string]float64{
+			"electric": 2, "ice": 2, "rock": 2,
+			"grass": 0.5, "bug": 0.5, "ghost": 0, "ground": 0,
+		}},
+	}
+	for _, tc := range cases {
+		got, err := c.DamageTaken(tc.defender)
+		if err != nil {
+			t.Errorf("%v: unexpected error: %v", tc.defender, err)
+			continue
+		}
+		if !maps.Equal(got, tc.want) {
+			t.Errorf("%v: expected %v, got %v", tc.defender, tc.want, got)
+		}
+	}
+}
+
+func TestEffectivenessConcurrent(t *testing.T) {
+	c := newFixtureClient(t)
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got, err := c.Effectiveness("electric", []string{"water"})
+			if err != nil || got != 2 {
+				t.Errorf("expected 2, got %v (%v)", got, err)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,101 @@
+package pokecache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// diskStore keeps one JSON file per cache entry, named after a hash of the
+// key so arbitrary URLs map to safe file names.
+type diskStore struct {
+	dir string
+}
+
+type diskEntry struct {
+	Key       string    `json:"key"`
+	CreatedAt time.Time `json:"created_at"`
+	Val       []byte    `json:"val"`
+}
+
+func (d *diskStore) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(d.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+func (d *diskStore) put(key string, entry cacheEntry) error {
+	data, err := json.Marshal(diskEntry{
+		Key:       key,
+		CreatedAt: entry.createdAt,
+		Val:       entry.val,
+	})
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(d.dir, 0o755)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(d.dir, ".entry-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.Write(data)
+	if err != nil {
+		tmp.Close()
+		return err
+	}
+	err = tmp.Close()
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), d.path(key))
+}
+
+func readDiskEntry(path string) (diskEntry, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return diskEntry{}, false
+	}
+	var entry diskEntry
+	err = json.Unmarshal(data, &entry)
+	if err != nil {
+		return diskEntry{}, false
+	}
+	return entry, true
+}
+
+func (d *diskStore) get(key string) (cacheEntry, bool) {
+	entry, ok := readDiskEntry(d.path(key))
+	// Guard against hash collisions, however unlikely.
+	if !ok || entry.Key != key {
+		return cacheEntry{}, false
+	}
+	return cacheEntry{createdAt: entry.CreatedAt, val: entry.Val}, true
+}
+
+func (d *diskStore) remove(key string) {
+	os.Remove(d.path(key))
+}
+
+func (d *diskStore) reap(expired func(cacheEntry) bool) {
+	files, err := os.ReadDir(d.dir)
+	if err != nil {
+		return
+	}
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), ".json") {
+			continue
+		}
+		path := filepath.Join(d.dir, file.Name())
+		entry, ok := readDiskEntry(path)
+		if !ok || expired(cacheEntry{createdAt: entry.CreatedAt}) {
+			os.Remove(path)
+		}
+	}
+}
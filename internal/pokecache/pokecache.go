@@ -1,6 +1,8 @@
 package pokecache
 
 import (
+	"container/list"
+	"context"
 	"sync"
 	"time"
 )
@@ -8,54 +10,197 @@ import (
 type cacheEntry struct {
 	createdAt time.Time
 	val       []byte
+	elem      *list.Element
 }
 
 type Cache struct {
 	reapInterval time.Duration
 	entries      map[string]cacheEntry
 	mu           sync.Mutex
+	lru          *list.List // keys, most recently used at the front
+	size         int
+	maxEntries   int
+	maxBytes     int
+	disk         *diskStore
+	ctx          context.Context
+	cancel       context.CancelFunc
+	done         chan struct{}
 }
 
-func NewCache(interval time.Duration) *Cache {
+type Option func(*Cache)
+
+// WithDir adds an on-disk tier under dir. Get falls back to it on a memory
+// miss and Add writes through to it, so entries survive restarts.
+func WithDir(dir string) Option {
+	return func(c *Cache) {
+		c.disk = &diskStore{dir: dir}
+	}
+}
+
+// WithMaxEntries bounds the number of entries held in memory, evicting the
+// least recently used ones first. Zero means no limit.
+func WithMaxEntries(n int) Option {
+	return func(c *Cache) {
+		c.maxEntries = n
+	}
+}
+
+// WithMaxBytes bounds the total size of keys and values held in memory,
+// evicting the least recently used entries first. Zero means no limit.
+func WithMaxBytes(n int) Option {
+	return func(c *Cache) {
+		c.maxBytes = n
+	}
+}
+
+// WithContext ties the reaper goroutine to ctx; cancelling it has the same
+// effect as calling Close.
+func WithContext(ctx context.Context) Option {
+	return func(c *Cache) {
+		c.ctx = ctx
+	}
+}
+
+func NewCache(interval time.Duration, opts ...Option) *Cache {
 	c := Cache{
 		reapInterval: interval,
 		entries:      make(map[string]cacheEntry),
+		lru:          list.New(),
+		ctx:          context.Background(),
+		done:         make(chan struct{}),
 	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	c.ctx, c.cancel = context.WithCancel(c.ctx)
 	c.reapLoop()
 	return &c
 }
 
+// Close stops the reaper goroutine and waits for it to exit. Entries stay
+// readable afterwards but are no longer reaped. It is safe to call more
+// than once.
+func (c *Cache) Close() {
+	c.cancel()
+	<-c.done
+}
+
+// Add stores val under key. The disk write, if any, happens outside the
+// lock so readers hitting memory don't wait on it.
 func (c *Cache) Add(key string, val []byte) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-	c.entries[key] = cacheEntry{
+	entry := cacheEntry{
 		createdAt: time.Now(),
 		val:       val,
 	}
+	c.mu.Lock()
+	c.insert(key, entry)
+	c.mu.Unlock()
+	if c.disk != nil {
+		// The disk tier is best effort; a failed write just means a
+		// future process will have to fetch the entry again.
+		_ = c.disk.put(key, entry)
+	}
 }
 
 func (c *Cache) Get(key string) ([]byte, bool) {
 	c.mu.Lock()
-	defer c.mu.Unlock()
 	entry, exists := c.entries[key]
+	if exists {
+		c.lru.MoveToFront(entry.elem)
+	}
+	c.mu.Unlock()
+	if exists {
+		return entry.val, true
+	}
+	if c.disk == nil {
+		return []byte{}, false
+	}
+	entry, exists = c.disk.get(key)
 	if !exists {
 		return []byte{}, false
 	}
+	if c.expired(entry) {
+		c.disk.remove(key)
+		return []byte{}, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// Someone may have added a newer value while we read the disk.
+	if newer, ok := c.entries[key]; ok {
+		c.lru.MoveToFront(newer.elem)
+		return newer.val, true
+	}
+	c.insert(key, entry)
 	return entry.val, true
 }
 
+func entrySize(key string, entry cacheEntry) int {
+	return len(key) + len(entry.val)
+}
+
+// insert stores entry as the most recently used and evicts from the back
+// until the cache is within its bounds. Callers must hold c.mu.
+func (c *Cache) insert(key string, entry cacheEntry) {
+	if old, exists := c.entries[key]; exists {
+		c.remove(key, old)
+	}
+	entry.elem = c.lru.PushFront(key)
+	c.entries[key] = entry
+	c.size += entrySize(key, entry)
+	for c.overLimit() {
+		oldest := c.lru.Back().Value.(string)
+		c.remove(oldest, c.entries[oldest])
+	}
+}
+
+func (c *Cache) overLimit() bool {
+	if c.lru.Len() == 0 {
+		return false
+	}
+	if c.maxEntries > 0 && c.lru.Len() > c.maxEntries {
+		return true
+	}
+	return c.maxBytes > 0 && c.size > c.maxBytes
+}
+
+// remove drops key from memory. Callers must hold c.mu.
+func (c *Cache) remove(key string, entry cacheEntry) {
+	c.lru.Remove(entry.elem)
+	delete(c.entries, key)
+	c.size -= entrySize(key, entry)
+}
+
+func (c *Cache) expired(entry cacheEntry) bool {
+	return time.Since(entry.createdAt) > c.reapInterval
+}
+
+// reap drops expired entries from memory, then scans the disk tier without
+// holding the lock, since that can take a while for a big cache.
+func (c *Cache) reap() {
+	c.mu.Lock()
+	for key, entry := range c.entries {
+		if c.expired(entry) {
+			c.remove(key, entry)
+		}
+	}
+	c.mu.Unlock()
+	if c.disk != nil {
+		c.disk.reap(c.expired)
+	}
+}
+
 func (c *Cache) reapLoop() {
 	ticker := time.NewTicker(c.reapInterval)
 	go func() {
+		defer close(c.done)
+		defer ticker.Stop()
 		for {
-			<-ticker.C
-			c.mu.Lock()
-			for key, entry := range c.entries {
-				if time.Since(entry.createdAt) > c.reapInterval {
-					delete(c.entries, key)
-				}
+			select {
+			case <-c.ctx.Done():
+				return
+			case <-ticker.C:
+				c.reap()
 			}
-			c.mu.Unlock()
 		}
 	}()
 }
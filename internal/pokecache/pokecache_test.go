@@ -1,7 +1,10 @@
 package pokecache
 
 import (
+	"context"
 	"fmt"
+	"runtime"
+	"sync"
 	"testing"
 	"time"
 )
@@ -25,6 +28,7 @@ func TestAddGet(t *testing.T) {
 	for i, c := range cases {
 		t.Run(fmt.Sprintf("Test case %v", i), func(t *testing.T) {
 			cache := NewCache(interval)
+			defer cache.Close()
 			cache.Add(c.key, c.val)
 			val, ok := cache.Get(c.key)
 			if !ok {
@@ -43,6 +47,7 @@ func TestReapLoop(t *testing.T) {
 	const baseTime = 5 * time.Millisecond
 	const waitTime = baseTime + 5*time.Millisecond
 	cache := NewCache(baseTime)
+	defer cache.Close()
 	cache.Add("https://example.com", []byte("testdata"))
 
 	_, ok := cache.Get("https://example.com")
@@ -59,3 +64,139 @@ func TestReapLoop(t *testing.T) {
 		return
 	}
 }
+
+func TestDiskSurvivesRestart(t *testing.T) {
+	const interval = 5 * time.Second
+	dir := t.TempDir()
+	cache := NewCache(interval, WithDir(dir))
+	defer cache.Close()
+	cache.Add("https://example.com", []byte("testdata"))
+
+	restarted := NewCache(interval, WithDir(dir))
+	defer restarted.Close()
+	val, ok := restarted.Get("https://example.com")
+	if !ok {
+		t.Errorf("expected to find key on disk")
+		return
+	}
+	if string(val) != "testdata" {
+		t.Errorf("expected to find value on disk")
+		return
+	}
+}
+
+func TestDiskConcurrent(t *testing.T) {
+	cache := NewCache(time.Millisecond, WithDir(t.TempDir()))
+	defer cache.Close()
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range 50 {
+				key := fmt.Sprintf("https://example.com/%d", (i+j)%10)
+				cache.Add(key, []byte("testdata"))
+				cache.Get(key)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestDiskRespectsTTL(t *testing.T) {
+	const baseTime = 5 * time.Millisecond
+	const waitTime = baseTime + 5*time.Millisecond
+	dir := t.TempDir()
+	cache := NewCache(time.Hour, WithDir(dir))
+	defer cache.Close()
+	cache.Add("https://example.com", []byte("testdata"))
+
+	time.Sleep(waitTime)
+
+	restarted := NewCache(baseTime, WithDir(dir))
+	defer restarted.Close()
+	_, ok := restarted.Get("https://example.com")
+	if ok {
+		t.Errorf("expected expired disk entry to be ignored")
+		return
+	}
+}
+
+func waitForGoroutines(t *testing.T, want int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > want {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected at most %d goroutines, have %d", want, runtime.NumGoroutine())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestClose(t *testing.T) {
+	before := runtime.NumGoroutine()
+	cache := NewCache(time.Millisecond)
+	cache.Add("https://example.com", []byte("testdata"))
+	cache.Close()
+	cache.Close()
+	waitForGoroutines(t, before)
+
+	_, ok := cache.Get("https://example.com")
+	if !ok {
+		t.Errorf("expected to find key after close")
+		return
+	}
+}
+
+func TestContextCancel(t *testing.T) {
+	before := runtime.NumGoroutine()
+	ctx, cancel := context.WithCancel(context.Background())
+	NewCache(time.Millisecond, WithContext(ctx))
+	cancel()
+	waitForGoroutines(t, before)
+}
+
+func TestMaxEntries(t *testing.T) {
+	cache := NewCache(5*time.Second, WithMaxEntries(2))
+	defer cache.Close()
+	cache.Add("a", []byte("1"))
+	cache.Add("b", []byte("2"))
+	// Touch "a" so "b" becomes the least recently used.
+	cache.Get("a")
+	cache.Add("c", []byte("3"))
+
+	cases := []struct {
+		key  string
+		want bool
+	}{
+		{key: "a", want: true},
+		{key: "b", want: false},
+		{key: "c", want: true},
+	}
+	for _, c := range cases {
+		_, ok := cache.Get(c.key)
+		if ok != c.want {
+			t.Errorf("key %s: expected found=%v, got %v", c.key, c.want, ok)
+		}
+	}
+}
+
+func TestMaxBytes(t *testing.T) {
+	cache := NewCache(5*time.Second, WithMaxBytes(10))
+	defer cache.Close()
+	cache.Add("a", []byte("1234"))
+	cache.Add("b", []byte("1234"))
+	cache.Add("c", []byte("1234"))
+
+	if _, ok := cache.Get("a"); ok {
+		t.Errorf("expected oldest key to be evicted")
+	}
+	if _, ok := cache.Get("c"); !ok {
+		t.Errorf("expected newest key to be kept")
+	}
+
+	cache.Add("huge", make([]byte, 100))
+	if _, ok := cache.Get("huge"); ok {
+		t.Errorf("expected oversized value not to be kept")
+	}
+}
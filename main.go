@@ -1,211 +1,142 @@
 package main
 
 import (
-	"bufio"
+	"errors"
+	"flag"
 	"fmt"
+	"net/http"
 	"os"
-	"strings"
+	"path/filepath"
 
+	"github.com/tquid/pokedexcli/internal/output"
 	"github.com/tquid/pokedexcli/internal/pokeapi"
 )
 
-type cliCommand struct {
-	name        string
-	description string
-	callback    func([]string) error
+func usage() {
+	w := flag.CommandLine.Output()
+	fmt.Fprintf(w, "Usage: %s [flags] [script]\n\n", os.Args[0])
+	fmt.Fprintln(w, "With no script or -c, commands are read from stdin; interactively if it's a terminal.")
+	fmt.Fprintln(w, "The exit status is 1 if any command in a script, -c or piped input failed.")
+	fmt.Fprintln(w, "With -record or -replay, the disk cache is off and the Pokedex is saved to a temporary file, deleted on exit.")
+	fmt.Fprintln(w)
+	flag.PrintDefaults()
 }
 
-func initCommands(client *pokeapi.Client) map[string]cliCommand {
-	return map[string]cliCommand{
-		"catch": {
-			name:        "catch",
-			description: "Try to catch a Pokemon",
-			callback:    func(params []string) error { return commandCatch(client, params) },
-		},
-		"inspect": {
-			name:        "inspect",
-			description: "Show Pokemon details",
-			callback:    func(params []string) error { return commandInspect(client, params) },
-		},
-		"exit": {
-			name:        "exit",
-			description: "Exit the Pokedex",
-			callback:    commandExit,
-		},
-		"explore": {
-			name:        "explore",
-			description: "Explore an area (use 'explore <area>')",
-			callback:    func(params []string) error { return commandExplore(client, params) },
-		},
-		"help": {
-			name:        "help",
-			description: "Displays a help message",
-			callback:    commandHelp,
-		},
-		"map": {
-			name:        "map",
-			description: "show next 20 map entries",
-			callback:    func([]string) error { return commandMap(client) },
-		},
-		"mapb": {
-			name:        "mapb",
-			description: "show previous 20 map entries",
-			callback:    func([]string) error { return commandMapb(client) },
-		},
-		"pokedex": {
-			name:        "pokedex",
-			description: "show your pokedex",
-			callback:    func([]string) error { return commandPokedex(client) },
-		},
-	}
-}
-
-func commandCatch(c *pokeapi.Client, params []string) error {
-	if len(params) == 0 {
-		return fmt.Errorf("'catch' command requires a pokemon name, e.g. 'catch pikachu'")
-	}
-	pokemonName := params[0]
-	pokemon, err := c.GetPokemon(pokemonName)
-	if err != nil {
-		return fmt.Errorf("error getting pokemon info: %w", err)
-	}
-	fmt.Printf("Throwing a Pokeball at %s...\n", pokemonName)
-	if pokemon.Catch() {
-		fmt.Printf("%s was caught!\n", pokemonName)
-		fmt.Println("You may now inspect it with the inspect command.")
-		c.AddPokedexEntry(pokemon)
-	} else {
-		fmt.Printf("%s escaped!\n", pokemonName)
-	}
-	return nil
+func startupError(err error) {
+	out.Print(errorResult{Error: errorDetail{
+		Kind:    "error",
+		Message: fmt.Sprintf("Error starting pokedex: %v", err),
+	}})
 }
 
-func commandInspect(c *pokeapi.Client, params []string) error {
-	if len(params) == 0 {
-		return fmt.Errorf("'inspect' command requires a pokemon name, e.g. 'inspect pikachu'")
-	}
-	pokemonName := params[0]
-	pokemon, ok := c.GetPokedexEntry(pokemonName)
-	if !ok {
-		fmt.Println("you have not caught that pokemon (or it doesn't exist)")
-		return nil
-	}
-	fmt.Printf("Name: %s\n", pokemon.Name)
-	fmt.Printf("Height: %d\n", pokemon.Height)
-	fmt.Printf("Weight: %d\n", pokemon.Weight)
-	fmt.Println("Stats:")
-	for _, stat := range pokemon.Stats {
-		fmt.Printf("  -%s: %d\n", stat.Stat.Name, stat.BaseStat)
-	}
-	fmt.Println("Types:")
-	for _, pokemonType := range pokemon.Types {
-		fmt.Printf("  - %s\n", pokemonType.Type.Name)
-	}
-	return nil
-}
-
-func commandPokedex(c *pokeapi.Client) error {
-	fmt.Println("Your Pokedex:")
-	pokedex := c.ListPokedex()
-	if len(pokedex) == 0 {
-		fmt.Println(" Nothing yet!")
-		return nil
-	}
-	for _, name := range pokedex {
-		fmt.Printf(" - %s\n", name)
-	}
-	return nil
-}
-
-func commandHelp([]string) error {
-	fmt.Println("help: print this helpful message\nexit: exit the pokedex")
-	return nil
-}
-
-func commandExit([]string) error {
-	os.Exit(0)
-	return nil
-}
-
-func commandMap(c *pokeapi.Client) error {
-	err := c.NextLocationAreas()
-	if err != nil {
-		fmt.Printf("Error getting next map chunk: %v\n", err)
-	}
-	for _, name := range c.GetLocationNames() {
-		fmt.Println(name)
-	}
-	return nil
+func main() {
+	os.Exit(run())
 }
 
-func commandMapb(c *pokeapi.Client) error {
-	err := c.PreviousLocationAreas()
+// run is main without os.Exit, so deferred cleanup happens on every path.
+// It returns the exit status.
+func run() int {
+	apiURL := flag.String("api-url", os.Getenv("POKEDEX_API_URL"), "base URL of the PokeAPI v2 server (env POKEDEX_API_URL)")
+	recordDir := flag.String("record", "", "save every API response as a fixture in this directory")
+	replayDir := flag.String("replay", "", "serve API responses from fixtures in this directory instead of the network")
+	command := flag.String("c", "", "run a single command and exit")
+	outputFormat := flag.String("output", "text", "print results as text, json or yaml")
+	seed := flag.Int64("seed", 0, "seed for encounters and catches, to replay a session (default random)")
+	flag.Usage = usage
+	flag.Parse()
+	if flag.NArg() > 1 || (flag.NArg() == 1 && *command != "") {
+		flag.Usage()
+		return 2
+	}
+	format, err := output.ParseFormat(*outputFormat)
 	if err != nil {
-		fmt.Printf("Error getting previous map chunk: %v\n", err)
-	}
-	for _, name := range c.GetLocationNames() {
-		fmt.Println(name)
+		fmt.Fprintf(flag.CommandLine.Output(), "%v\n\n", err)
+		flag.Usage()
+		return 2
 	}
-	return nil
-}
+	out.SetFormat(format)
 
-func commandExplore(c *pokeapi.Client, params []string) error {
-	var areaName string
-	if len(params) == 0 {
-		return fmt.Errorf("'explore' command requires an area name, e.g. 'explore canalave-city-area'")
-	}
-	areaName = params[0]
-	pokemonList, err := c.ExploreArea(areaName)
-	if err != nil {
-		return fmt.Errorf("exploring area %s: %v\n", areaName, err)
-	}
-	fmt.Printf("Exploring %s...\n", areaName)
-	if len(pokemonList) == 0 {
-		fmt.Println("No pokemon found!")
-		return nil
-	}
-	fmt.Println("Found pokemon:")
-	for _, pokemon := range pokemonList {
-		fmt.Printf(" - %s\n", pokemon)
+	var opts []pokeapi.Option
+	if *apiURL != "" {
+		opts = append(opts, pokeapi.WithBaseURL(*apiURL))
+	}
+	fixtureMode := *recordDir != "" || *replayDir != ""
+	switch {
+	case *recordDir != "" && *replayDir != "":
+		startupError(errors.New("-record and -replay can't be used together"))
+		return 2
+	case *recordDir != "":
+		opts = append(opts, pokeapi.WithHTTPClient(&http.Client{
+			Transport: pokeapi.NewRecordTransport(*recordDir, nil),
+		}))
+	case *replayDir != "":
+		// A missing fixture won't appear by trying again.
+		opts = append(opts,
+			pokeapi.WithHTTPClient(&http.Client{Transport: pokeapi.NewReplayTransport(*replayDir)}),
+			pokeapi.WithRetry(pokeapi.NoRetry),
+		)
+	}
+	// Seed 0 is as good as any other, so check whether it was asked for.
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "seed" {
+			opts = append(opts, pokeapi.WithSeed(*seed))
+		}
+	})
+	if fixtureMode {
+		// The disk cache would answer requests before they reach the
+		// fixtures, and a recorded or replayed run shouldn't touch your
+		// real save, so it gets a fresh one.
+		saveDir, err := os.MkdirTemp("", "pokedexcli-")
+		if err != nil {
+			startupError(err)
+			return 1
+		}
+		defer os.RemoveAll(saveDir)
+		opts = append(opts, pokeapi.WithSavePath(filepath.Join(saveDir, "save.json")))
+	} else if cacheDir, err := pokeapi.DefaultCacheDir(); err == nil {
+		opts = append(opts, pokeapi.WithDiskCache(cacheDir))
 	}
-	return nil
-}
-
-func promptAndRead() ([]string, error) {
-	fmt.Print("pokedex > ")
-	s := bufio.NewScanner(os.Stdin)
-	s.Scan()
-	err := s.Err()
+	c, err := pokeapi.NewClient(opts...)
 	if err != nil {
-		return nil, fmt.Errorf("error trying to scan input: %w", err)
+		startupError(err)
+		return 1
+	}
+	// Stderr keeps the seed out of structured output, but it's there if a
+	// bug report needs the session replayed.
+	fmt.Fprintf(os.Stderr, "Random seed: %d (replay with -seed %d)\n", c.Seed(), c.Seed())
+	s := &session{
+		cmds:       initCommands(c),
+		battleCmds: initBattleCommands(c),
+		client:     c,
+	}
+	s.interactive = *command == "" && flag.NArg() == 0 && editor.Interactive()
+	if !s.interactive {
+		stop := s.catchInterrupts()
+		defer stop()
 	}
-	return strings.Fields(s.Text()), nil
-}
 
-func main() {
-	c := pokeapi.NewClient()
-	cmds := initCommands(c)
-
-	for {
-		fields, err := promptAndRead()
-		var params []string
-		if len(fields) == 0 {
-			continue
-		}
-		command := fields[0]
-		if len(fields) > 1 {
-			params = fields[1:]
-		}
-		if err != nil {
-			fmt.Printf("Command error: %v\n", err)
-		}
-		if _, exists := cmds[command]; exists {
-			err = cmds[command].callback(params)
-			if err != nil {
-				fmt.Printf("Error trying command: %v\n", err)
+	switch {
+	case *command != "":
+		s.execute(*command)
+	case flag.NArg() == 1:
+		s.runScript(flag.Arg(0))
+	default:
+		if s.interactive {
+			editor.SetCompleter(completer(s.commands, c))
+			if path, err := historyPath(); err == nil {
+				err = editor.SetHistoryFile(path)
+				if err != nil {
+					fmt.Printf("Error loading history: %v\n", err)
+				}
 			}
-		} else {
-			fmt.Printf("unknown command '%s'\n", command)
 		}
+		s.readLoop()
+	}
+	s.finish()
+
+	// A typo at the prompt shouldn't make an interactive session "fail".
+	if s.failed && !s.interactive {
+		return 1
 	}
+	return 0
 }
@@ -0,0 +1,338 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/tquid/pokedexcli/internal/lineedit"
+	"github.com/tquid/pokedexcli/internal/pokeapi"
+)
+
+var editor = lineedit.New(os.Stdin, os.Stdout)
+
+func (s *session) prompt() string {
+	// Scripts piped to stdin don't want a prompt cluttering their output.
+	if !editor.Interactive() {
+		return ""
+	}
+	if s.client.InBattle() {
+		return "battle > "
+	}
+	return "pokedex > "
+}
+
+func confirm(question string) bool {
+	answer, err := editor.Ask(question + " [y/N] ")
+	if err != nil {
+		return false
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// completer offers command names for the first word, then whatever fits the
+// command's argument: caught Pokemon for inspect and matchup, areas on the current map
+// page for explore, the wild Pokemon for catch and your Pokemon's moves for
+// fight. cmds is called each time, as battles change what's available.
+func completer(cmds func() map[string]cliCommand, c *pokeapi.Client) lineedit.Completer {
+	return func(head string) []string {
+		fields := strings.Fields(head)
+		typingWord := !strings.HasSuffix(head, " ")
+		if len(fields) == 0 || (len(fields) == 1 && typingWord) {
+			return slices.Sorted(maps.Keys(cmds()))
+		}
+		// Every command takes at most one argument worth completing.
+		if len(fields) > 2 || (len(fields) == 2 && !typingWord) {
+			return nil
+		}
+		switch fields[0] {
+		case "inspect", "matchup":
+			return c.ListPokedex()
+		case "explore":
+			return c.GetLocationNames()
+		case "catch":
+			if c.InBattle() {
+				return ballNames()
+			}
+			if wild, ok := c.Wild(); ok {
+				return []string{wild.Pokemon}
+			}
+		case "fight":
+			if status, ok := c.Battle(); ok {
+				var names []string
+				for _, m := range status.Active.Moves {
+					names = append(names, m.Name)
+				}
+				return names
+			}
+		}
+		return nil
+	}
+}
+
+func historyPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "pokedexcli", "history"), nil
+}
+
+// offerSuggestion asks whether to rerun a command that failed on an unknown
+// name with the closest known name instead, and returns the corrected
+// params if so.
+func offerSuggestion(command string, params []string, err error) ([]string, bool) {
+	var notFound *pokeapi.NotFoundError
+	if !errors.As(err, &notFound) || len(notFound.Suggestions) == 0 {
+		return nil, false
+	}
+	i := slices.Index(params, notFound.Name)
+	if i < 0 {
+		return nil, false
+	}
+	suggestion := notFound.Suggestions[0]
+	if !confirm(fmt.Sprintf("Run '%s %s' instead?", command, suggestion)) {
+		return nil, false
+	}
+	corrected := slices.Clone(params)
+	corrected[i] = suggestion
+	return corrected, true
+}
+
+// runCommand runs cmd with a context that Ctrl-C cancels, so an interrupt
+// aborts a slow API call rather than killing the REPL.
+func runCommand(cmd cliCommand, params []string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	err := cmd.callback(ctx, params)
+	if ctx.Err() != nil {
+		fmt.Println()
+		return fmt.Errorf("'%s' %w", cmd.name, errInterrupted)
+	}
+	return err
+}
+
+var errInterrupted = errors.New("interrupted")
+
+// errorKind names the class of err for structured output, so tools can
+// tell a typo from an outage without parsing messages.
+func errorKind(err error) string {
+	switch {
+	case errors.Is(err, pokeapi.ErrNotFound):
+		return "not_found"
+	case errors.Is(err, pokeapi.ErrRateLimited):
+		return "rate_limited"
+	case errors.Is(err, pokeapi.ErrServer):
+		return "server"
+	case errors.Is(err, pokeapi.ErrNetwork):
+		return "network"
+	case errors.Is(err, pokeapi.ErrDecode):
+		return "decode"
+	case errors.Is(err, errInterrupted):
+		return "interrupted"
+	}
+	return "error"
+}
+
+// describeError turns API failures into advice the user can act on, falling
+// back to the full error chain for anything else.
+func describeError(err error) string {
+	var notFound *pokeapi.NotFoundError
+	switch {
+	case errors.As(err, &notFound) && len(notFound.Suggestions) > 0:
+		return fmt.Sprintf("%s not found, did you mean %s?", notFound.Name, strings.Join(notFound.Suggestions, " or "))
+	case errors.As(err, &notFound):
+		return fmt.Sprintf("%s not found", notFound.Name)
+	case errors.Is(err, pokeapi.ErrRateLimited):
+		return "PokeAPI is rate limiting us, wait a minute and try again"
+	case errors.Is(err, pokeapi.ErrServer):
+		return "PokeAPI is having problems right now, try again later"
+	case errors.Is(err, pokeapi.ErrNetwork):
+		return fmt.Sprintf("can't reach PokeAPI, check your connection (%v)", err)
+	case errors.Is(err, pokeapi.ErrDecode):
+		return fmt.Sprintf("PokeAPI sent something unexpected (%v)", err)
+	}
+	return err.Error()
+}
+
+// A session runs commands from one source: the REPL, a script or -c.
+type session struct {
+	cmds map[string]cliCommand
+	// battleCmds replace cmds while a battle is on.
+	battleCmds  map[string]cliCommand
+	client      *pokeapi.Client
+	interactive bool
+	// location prefixes error messages, e.g. "script.txt:3", so failures in
+	// a script can be found.
+	location string
+	failed   bool
+	// interrupts receives Ctrl-C outside the REPL, see catchInterrupts.
+	interrupts <-chan os.Signal
+}
+
+// execute runs one line of input and reports whether to keep going. Outside
+// the REPL, an interrupted command stops the session.
+func (s *session) execute(line string) bool {
+	fields := strings.Fields(line)
+	if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+		return true
+	}
+	command, params := fields[0], fields[1:]
+	cmd, exists := lookupCommand(s.commands(), command)
+	if !exists && s.client.InBattle() {
+		s.report("unknown_command", fmt.Sprintf("unknown command '%s', in a battle use fight, switch, catch or run", command))
+		return true
+	}
+	if !exists {
+		s.report("unknown_command", fmt.Sprintf("unknown command '%s'", command))
+		return true
+	}
+	err := runCommand(cmd, params)
+	if errors.Is(err, errExit) {
+		return false
+	}
+	if err == nil {
+		return true
+	}
+	s.reportCommandError(err)
+	if !s.interactive {
+		// Ctrl-C means stop the whole script, not just this line.
+		return !errors.Is(err, errInterrupted)
+	}
+	if corrected, ok := offerSuggestion(command, params, err); ok {
+		err = runCommand(cmd, corrected)
+		if err != nil {
+			s.reportCommandError(err)
+		}
+	}
+	return true
+}
+
+// commands returns the commands available right now.
+func (s *session) commands() map[string]cliCommand {
+	if s.client.InBattle() {
+		return s.battleCmds
+	}
+	return s.cmds
+}
+
+func (s *session) reportCommandError(err error) {
+	detail := errorDetail{
+		Kind:     errorKind(err),
+		Message:  describeError(err),
+		Location: s.location,
+	}
+	var notFound *pokeapi.NotFoundError
+	if errors.As(err, &notFound) {
+		detail.Suggestions = notFound.Suggestions
+	}
+	if !out.Structured() {
+		detail.Message = "Error trying command: " + detail.Message
+	}
+	s.fail(detail)
+}
+
+func (s *session) report(kind, msg string) {
+	s.fail(errorDetail{Kind: kind, Message: msg, Location: s.location})
+}
+
+func (s *session) fail(detail errorDetail) {
+	s.failed = true
+	out.Print(errorResult{Error: detail})
+}
+
+// catchInterrupts keeps Ctrl-C from killing a script or piped session
+// between commands, so it stops the way an interrupted command does:
+// reported, with a failing exit status and the Pokedex saved. Call the
+// returned func to restore the default behaviour.
+func (s *session) catchInterrupts() (stop func()) {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt)
+	s.interrupts = sigs
+	editor.SetInterrupt(sigs)
+	return func() {
+		signal.Stop(sigs)
+		editor.SetInterrupt(nil)
+	}
+}
+
+// interrupted reports whether Ctrl-C was pressed since it was last checked.
+func (s *session) interrupted() bool {
+	select {
+	case <-s.interrupts:
+		return true
+	default:
+		return false
+	}
+}
+
+// readLoop runs commands typed at the prompt, or piped to stdin, until exit
+// or end of input.
+func (s *session) readLoop() {
+	for {
+		line, err := editor.ReadLine(s.prompt())
+		if errors.Is(err, lineedit.ErrInterrupted) && !s.interactive {
+			s.report("interrupted", "interrupted")
+			return
+		}
+		if errors.Is(err, lineedit.ErrInterrupted) {
+			continue
+		}
+		if errors.Is(err, io.EOF) {
+			return
+		}
+		if err != nil {
+			s.report("error", fmt.Sprintf("Command error: %v", err))
+			return
+		}
+		if !s.execute(line) {
+			return
+		}
+	}
+}
+
+// runScript runs each line of the file at path as a command. Blank lines
+// and lines starting with # are skipped. A failing command doesn't stop the
+// script, but is reflected in the exit status.
+func (s *session) runScript(path string) {
+	f, err := os.Open(path)
+	if err != nil {
+		s.report("error", fmt.Sprintf("Error opening script: %v", err))
+		return
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		s.location = fmt.Sprintf("%s:%d", path, lineNum)
+		if s.interrupted() {
+			s.report("interrupted", "interrupted")
+			break
+		}
+		if !s.execute(scanner.Text()) {
+			break
+		}
+	}
+	s.location = ""
+	err = scanner.Err()
+	if err != nil {
+		s.report("error", fmt.Sprintf("Error reading script: %v", err))
+	}
+}
+
+// finish saves the Pokedex and releases the client.
+func (s *session) finish() {
+	err := s.client.Save()
+	if err != nil {
+		s.report("error", fmt.Sprintf("Error saving pokedex: %v", err))
+	}
+	s.client.Close()
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tquid/pokedexcli/internal/lineedit"
+	"github.com/tquid/pokedexcli/internal/output"
+)
+
+func TestReadLoopInterruptedWaitingForInput(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("can't send SIGINT on windows")
+	}
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer r.Close()
+	defer w.Close()
+	savedEditor, savedOut := editor, out
+	defer func() { editor, out = savedEditor, savedOut }()
+	editor = lineedit.New(r, io.Discard)
+	var buf bytes.Buffer
+	out = output.NewPrinter(&buf, output.Text)
+
+	s := &session{}
+	stop := s.catchInterrupts()
+	defer stop()
+	done := make(chan struct{})
+	go func() {
+		s.readLoop()
+		close(done)
+	}()
+	// Give the loop time to block on the empty pipe.
+	time.Sleep(50 * time.Millisecond)
+	p, err := os.FindProcess(os.Getpid())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err = p.Signal(os.Interrupt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("read loop didn't stop on SIGINT")
+	}
+	if !s.failed {
+		t.Errorf("expected an interrupted session to fail")
+	}
+	if !strings.Contains(buf.String(), "interrupted") {
+		t.Errorf("expected interrupted to be reported, got %q", buf.String())
+	}
+}
@@ -0,0 +1,629 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/tquid/pokedexcli/internal/output"
+	"github.com/tquid/pokedexcli/internal/pokeapi"
+)
+
+// out prints every command result, in the format picked with -output or
+// the output command.
+var out = output.NewPrinter(os.Stdout, output.Text)
+
+type messageResult struct {
+	Message string `json:"message"`
+}
+
+func (r messageResult) WriteText(w io.Writer) error {
+	_, err := fmt.Fprintln(w, r.Message)
+	return err
+}
+
+type seedResult struct {
+	Seed int64 `json:"seed"`
+	// Set is true if the seed was just changed.
+	Set bool `json:"set"`
+}
+
+func (r seedResult) WriteText(w io.Writer) error {
+	if r.Set {
+		_, err := fmt.Fprintf(w, "Random seed set to %d\n", r.Seed)
+		return err
+	}
+	_, err := fmt.Fprintf(w, "Random seed: %d\n", r.Seed)
+	return err
+}
+
+type errorResult struct {
+	Error errorDetail `json:"error"`
+}
+
+type errorDetail struct {
+	Kind        string   `json:"kind"`
+	Message     string   `json:"message"`
+	Location    string   `json:"location,omitempty"`
+	Suggestions []string `json:"suggestions,omitempty"`
+}
+
+func (r errorResult) WriteText(w io.Writer) error {
+	msg := r.Error.Message
+	if r.Error.Location != "" {
+		msg = r.Error.Location + ": " + msg
+	}
+	_, err := fmt.Fprintln(w, msg)
+	return err
+}
+
+type locationsResult struct {
+	Locations []string `json:"locations"`
+}
+
+func (r locationsResult) WriteText(w io.Writer) error {
+	for _, name := range r.Locations {
+		_, err := fmt.Fprintln(w, name)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type exploreResult struct {
+	Area    string   `json:"area"`
+	Pokemon []string `json:"pokemon"`
+}
+
+func (r exploreResult) WriteText(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Exploring %s...\n", r.Area)
+	if len(r.Pokemon) == 0 {
+		b.WriteString("No pokemon found!\n")
+	} else {
+		b.WriteString("Found pokemon:\n")
+		for _, pokemon := range r.Pokemon {
+			fmt.Fprintf(&b, " - %s\n", pokemon)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+type encounterResult struct {
+	Pokemon string `json:"pokemon"`
+	Level   int    `json:"level"`
+	Method  string `json:"method"`
+	Version string `json:"version"`
+	Area    string `json:"area"`
+}
+
+func (r encounterResult) WriteText(w io.Writer) error {
+	_, err := fmt.Fprintf(w, "A wild %s (Lv. %d) appeared!\n", r.Pokemon, r.Level)
+	return err
+}
+
+type catchResult struct {
+	Pokemon string `json:"pokemon"`
+	Ball    string `json:"ball"`
+	// BallName is for people, e.g. "Great Ball".
+	BallName  string `json:"ball_name"`
+	BallsLeft int    `json:"balls_left"`
+	Caught    bool   `json:"caught"`
+	// Shakes is how often the ball shook, 0 to 3.
+	Shakes int `json:"shakes"`
+	// Owned is the new individual, if it was caught.
+	Owned *ownedResult `json:"owned,omitempty"`
+	// Turn is the wild Pokemon's reply when it breaks free in a battle.
+	Turn *turnResult `json:"turn,omitempty"`
+}
+
+func (r catchResult) WriteText(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Throwing %s %s at %s... (%d left)\n", article(r.BallName), r.BallName, r.Pokemon, r.BallsLeft)
+	switch r.Shakes {
+	case 0:
+	case 1:
+		b.WriteString("It shook 1 time...\n")
+	default:
+		fmt.Fprintf(&b, "It shook %d times...\n", r.Shakes)
+	}
+	if r.Caught {
+		fmt.Fprintf(&b, "%s was caught!\n", r.Pokemon)
+		if r.Owned != nil {
+			fmt.Fprintf(&b, "It's #%d in your box. You may now inspect it with the inspect command.\n", r.Owned.ID)
+		} else {
+			b.WriteString("You may now inspect it with the inspect command.\n")
+		}
+	} else {
+		fmt.Fprintf(&b, "%s escaped!\n", r.Pokemon)
+	}
+	if r.Turn != nil {
+		r.Turn.write(&b)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+type moveResult struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	DamageClass string `json:"damage_class"`
+	Power       int    `json:"power"`
+	Accuracy    int    `json:"accuracy"`
+	PP          int    `json:"pp"`
+}
+
+type battlerResult struct {
+	Name    string `json:"name"`
+	Species string `json:"species"`
+	// ID is the box ID, absent for the wild Pokemon.
+	ID    int          `json:"id,omitempty"`
+	Level int          `json:"level"`
+	HP    int          `json:"hp"`
+	MaxHP int          `json:"max_hp"`
+	Types []string     `json:"types"`
+	Moves []moveResult `json:"moves"`
+	// Status is a condition like "paralysis", which makes the wild
+	// Pokemon easier to catch.
+	Status string `json:"status,omitempty"`
+}
+
+type battleResult struct {
+	Wild   battlerResult `json:"wild"`
+	Active battlerResult `json:"active"`
+	// Started is true if the battle just began, rather than being looked at.
+	Started bool `json:"started"`
+}
+
+func (r battleResult) WriteText(w io.Writer) error {
+	var b strings.Builder
+	if r.Started {
+		fmt.Fprintf(&b, "Go! %s!\n", r.Active.Name)
+	}
+	tw := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Wild %s\tLv. %d\tHP %d/%d%s\n", r.Wild.Name, r.Wild.Level, r.Wild.HP, r.Wild.MaxHP, statusColumn(r.Wild.Status))
+	fmt.Fprintf(tw, "#%d %s\tLv. %d\tHP %d/%d%s\n", r.Active.ID, r.Active.Name, r.Active.Level, r.Active.HP, r.Active.MaxHP, statusColumn(r.Active.Status))
+	tw.Flush()
+	b.WriteString("Moves:\n")
+	writeMoves(&b, r.Active.Moves)
+	b.WriteString("Use 'fight <move>', 'switch <#id>', 'catch [ball]' or 'run'.\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func statusColumn(status string) string {
+	if status == "" {
+		return ""
+	}
+	return "\t" + status
+}
+
+func writeMoves(w io.Writer, moves []moveResult) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, m := range moves {
+		power, accuracy := "-", "-"
+		if m.Power > 0 {
+			power = fmt.Sprint(m.Power)
+		}
+		if m.Accuracy > 0 {
+			accuracy = fmt.Sprintf("%d%%", m.Accuracy)
+		}
+		fmt.Fprintf(tw, "  %s\t%s\t%s\tpower %s\taccuracy %s\n", m.Name, m.Type, m.DamageClass, power, accuracy)
+	}
+	tw.Flush()
+}
+
+type battleEventResult struct {
+	Attacker string `json:"attacker"`
+	Target   string `json:"target"`
+	Move     string `json:"move"`
+	Missed   bool   `json:"missed"`
+	Damage   int    `json:"damage"`
+	// Effectiveness multiplies the damage for the move's type; 1 is
+	// neutral.
+	Effectiveness float64 `json:"effectiveness"`
+	TargetHP      int     `json:"target_hp"`
+	TargetMaxHP   int     `json:"target_max_hp"`
+	Fainted       bool    `json:"fainted"`
+	// Status is the condition the attack caused, if any.
+	Status string `json:"status,omitempty"`
+}
+
+type turnResult struct {
+	Events []battleEventResult `json:"events"`
+	// Outcome is "won" or "lost" once the battle is over, and empty until
+	// then.
+	Outcome string `json:"outcome,omitempty"`
+	// MustSwitch is true if your Pokemon fainted but others can still
+	// fight.
+	MustSwitch bool `json:"must_switch"`
+}
+
+func (r turnResult) WriteText(w io.Writer) error {
+	var b strings.Builder
+	r.write(&b)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func (r turnResult) write(b *strings.Builder) {
+	for _, e := range r.Events {
+		fmt.Fprintf(b, "%s used %s!\n", e.Attacker, e.Move)
+		if e.Missed {
+			b.WriteString("It missed!\n")
+			continue
+		}
+		switch {
+		case e.Effectiveness == 0:
+			fmt.Fprintf(b, "It doesn't affect %s...\n", e.Target)
+		case e.Effectiveness > 1:
+			b.WriteString("It's super effective!\n")
+		case e.Effectiveness < 1:
+			b.WriteString("It's not very effective...\n")
+		}
+		if e.Damage > 0 {
+			fmt.Fprintf(b, "%s took %d damage (%d/%d HP)\n", e.Target, e.Damage, e.TargetHP, e.TargetMaxHP)
+		}
+		if e.Fainted {
+			fmt.Fprintf(b, "%s fainted!\n", e.Target)
+		}
+		if e.Status != "" {
+			fmt.Fprintf(b, "%s %s!\n", e.Target, statusVerbs[e.Status])
+		}
+	}
+	switch {
+	case r.Outcome == string(pokeapi.BattleWon):
+		b.WriteString("You won the battle!\n")
+	case r.Outcome == string(pokeapi.BattleLost):
+		b.WriteString("You have no pokemon left that can fight, so you ran away!\n")
+	case r.MustSwitch:
+		b.WriteString("Use 'switch <#id>' to send out another pokemon.\n")
+	}
+}
+
+var statusVerbs = map[string]string{
+	"paralysis": "is paralyzed",
+	"poison":    "was poisoned",
+	"burn":      "was burned",
+	"sleep":     "fell asleep",
+	"freeze":    "was frozen solid",
+}
+
+type switchResult struct {
+	Pokemon battlerResult `json:"pokemon"`
+	// Turn is the wild Pokemon's attack while you switched, if it got one.
+	Turn *turnResult `json:"turn,omitempty"`
+}
+
+func (r switchResult) WriteText(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Go! %s! (Lv. %d, HP %d/%d)\n", r.Pokemon.Name, r.Pokemon.Level, r.Pokemon.HP, r.Pokemon.MaxHP)
+	if r.Turn != nil {
+		r.Turn.write(&b)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+type statResult struct {
+	Name     string `json:"name"`
+	BaseStat int    `json:"base_stat"`
+}
+
+type inspectResult struct {
+	Name   string       `json:"name"`
+	Height int          `json:"height"`
+	Weight int          `json:"weight"`
+	Stats  []statResult `json:"stats"`
+	Types  []string     `json:"types"`
+	// Owned lists the individuals that matched, e.g. every pikachu you own.
+	Owned []ownedResult `json:"owned"`
+}
+
+func (r inspectResult) WriteText(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Name: %s\n", r.Name)
+	fmt.Fprintf(&b, "Height: %d\n", r.Height)
+	fmt.Fprintf(&b, "Weight: %d\n", r.Weight)
+	b.WriteString("Stats:\n")
+	for _, stat := range r.Stats {
+		fmt.Fprintf(&b, "  -%s: %d\n", stat.Name, stat.BaseStat)
+	}
+	b.WriteString("Types:\n")
+	for _, pokemonType := range r.Types {
+		fmt.Fprintf(&b, "  - %s\n", pokemonType)
+	}
+	b.WriteString("Owned:\n")
+	writeOwned(&b, r.Owned)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+type ownedResult struct {
+	ID       int    `json:"id"`
+	Species  string `json:"species"`
+	Nickname string `json:"nickname"`
+	Level    int    `json:"level"`
+	// CaughtAt is RFC 3339, or empty for Pokemon from before it was kept.
+	CaughtAt string `json:"caught_at"`
+	Location string `json:"location"`
+	Ball     string `json:"ball"`
+}
+
+func ownedResultFrom(o pokeapi.OwnedPokemon) ownedResult {
+	r := ownedResult{
+		ID:       o.ID,
+		Species:  o.Species,
+		Nickname: o.Nickname,
+		Level:    o.Level,
+		Location: o.Location,
+		Ball:     o.Ball,
+	}
+	if !o.CaughtAt.IsZero() {
+		r.CaughtAt = o.CaughtAt.Format(time.RFC3339)
+	}
+	return r
+}
+
+// writeOwned lines up owned Pokemon in columns, one per line.
+func writeOwned(w io.Writer, owned []ownedResult) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, o := range owned {
+		caughtAt, location := "unknown", "unknown"
+		if t, err := time.Parse(time.RFC3339, o.CaughtAt); err == nil {
+			caughtAt = t.Local().Format("2006-01-02 15:04")
+		}
+		if o.Location != "" {
+			location = o.Location
+		}
+		fmt.Fprintf(tw, "  #%d\t%s\t%s\tLv. %d\tcaught %s\tin %s\twith %s\n",
+			o.ID, o.Species, o.Nickname, o.Level, caughtAt, location, o.Ball)
+	}
+	tw.Flush()
+}
+
+type boxResult struct {
+	Pokemon []ownedResult `json:"pokemon"`
+}
+
+func (r boxResult) WriteText(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("Your box:\n")
+	if len(r.Pokemon) == 0 {
+		b.WriteString(" Empty!\n")
+	}
+	writeOwned(&b, r.Pokemon)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+type bagItemResult struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+	Description string `json:"description"`
+	Count       int    `json:"count"`
+	// CatchBonus multiplies the catch rate; 0 for anything but balls.
+	CatchBonus float64 `json:"catch_bonus"`
+}
+
+type bagResult struct {
+	Items []bagItemResult `json:"items"`
+}
+
+func (r bagResult) WriteText(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("Your bag:\n")
+	if len(r.Items) == 0 {
+		b.WriteString(" Empty!\n")
+	}
+	tw := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	for _, item := range r.Items {
+		fmt.Fprintf(tw, "  %d\t%s\t(%s)\t%s\n", item.Count, item.DisplayName, item.Name, item.Description)
+	}
+	tw.Flush()
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+type pokedexEntryResult struct {
+	Name   string `json:"name"`
+	Seen   bool   `json:"seen"`
+	Caught bool   `json:"caught"`
+	// Owned counts the individuals of this species in your box.
+	Owned int `json:"owned"`
+}
+
+type pokedexResult struct {
+	Pokemon []pokedexEntryResult `json:"pokemon"`
+}
+
+func (r pokedexResult) WriteText(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("Your Pokedex:\n")
+	if len(r.Pokemon) == 0 {
+		b.WriteString(" Nothing yet!\n")
+	}
+	for _, entry := range r.Pokemon {
+		fmt.Fprintf(&b, " - %s", entry.Name)
+		switch {
+		case !entry.Caught:
+			b.WriteString(" (seen)")
+		case entry.Owned > 1:
+			fmt.Fprintf(&b, " (x%d)", entry.Owned)
+		}
+		b.WriteString("\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+type progressResult struct {
+	Dex    string `json:"dex"`
+	Total  int    `json:"total"`
+	Seen   int    `json:"seen"`
+	Caught int    `json:"caught"`
+	// Completion is the percentage caught, to one decimal place.
+	Completion float64 `json:"completion"`
+}
+
+func (r progressResult) WriteText(w io.Writer) error {
+	_, err := fmt.Fprintf(w, "%s dex: seen %d, caught %d of %d (%.1f%% complete)\n",
+		r.Dex, r.Seen, r.Caught, r.Total, r.Completion)
+	return err
+}
+
+type helpEntryResult struct {
+	Name        string   `json:"name"`
+	Usage       string   `json:"usage"`
+	Description string   `json:"description"`
+	Aliases     []string `json:"aliases"`
+	Examples    []string `json:"examples"`
+}
+
+type helpResult struct {
+	Commands []helpEntryResult `json:"commands"`
+}
+
+func (r helpResult) WriteText(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("Welcome to the Pokedex!\n\nCommands:\n")
+	tw := tabwriter.NewWriter(&b, 0, 0, 3, ' ', 0)
+	for _, cmd := range r.Commands {
+		description := cmd.Description
+		if len(cmd.Aliases) > 0 {
+			description += fmt.Sprintf(" (alias %s)", strings.Join(cmd.Aliases, ", "))
+		}
+		fmt.Fprintf(tw, "  %s\t%s\n", cmd.Usage, description)
+	}
+	tw.Flush()
+	b.WriteString("\nRun 'help <command>' for examples.\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+type commandHelpResult struct {
+	Command helpEntryResult `json:"command"`
+}
+
+func (r commandHelpResult) WriteText(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Usage: %s\n\n%s\n", r.Command.Usage, r.Command.Description)
+	if len(r.Command.Aliases) > 0 {
+		fmt.Fprintf(&b, "\nAliases: %s\n", strings.Join(r.Command.Aliases, ", "))
+	}
+	if len(r.Command.Examples) > 0 {
+		b.WriteString("\nExamples:\n")
+		for _, example := range r.Command.Examples {
+			fmt.Fprintf(&b, "  %s\n", example)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+type typeMultiplierResult struct {
+	Type       string  `json:"type"`
+	Multiplier float64 `json:"multiplier"`
+}
+
+func (m typeMultiplierResult) String() string {
+	return fmt.Sprintf("%s x%s", m.Type, multiplier(m.Multiplier))
+}
+
+func multiplier(m float64) string {
+	return strconv.FormatFloat(m, 'g', -1, 64)
+}
+
+type matchupResult struct {
+	Pokemon     string                 `json:"pokemon"`
+	Types       []string               `json:"types"`
+	Weaknesses  []typeMultiplierResult `json:"weaknesses"`
+	Resistances []typeMultiplierResult `json:"resistances"`
+	Immunities  []string               `json:"immunities"`
+}
+
+func (r matchupResult) WriteText(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s (%s)\n", r.Pokemon, strings.Join(r.Types, ", "))
+	tw := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Weak to:\t%s\n", joinMultipliers(r.Weaknesses))
+	fmt.Fprintf(tw, "Resists:\t%s\n", joinMultipliers(r.Resistances))
+	fmt.Fprintf(tw, "Immune to:\t%s\n", cmp.Or(strings.Join(r.Immunities, ", "), "nothing"))
+	tw.Flush()
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func joinMultipliers(ms []typeMultiplierResult) string {
+	if len(ms) == 0 {
+		return "nothing"
+	}
+	parts := make([]string, len(ms))
+	for i, m := range ms {
+		parts[i] = m.String()
+	}
+	return strings.Join(parts, ", ")
+}
+
+type versusSideResult struct {
+	Pokemon string   `json:"pokemon"`
+	Types   []string `json:"types"`
+	// Attacks is how moves of each of its types fare against the other
+	// Pokemon, and Best the highest of those.
+	Attacks []typeMultiplierResult `json:"attacks"`
+	Best    float64                `json:"best"`
+}
+
+type versusResult struct {
+	First  versusSideResult `json:"first"`
+	Second versusSideResult `json:"second"`
+	// Advantage names whichever has the better best attack, and is empty
+	// if neither does.
+	Advantage string `json:"advantage"`
+}
+
+func (r versusResult) WriteText(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s (%s) vs %s (%s)\n",
+		r.First.Pokemon, strings.Join(r.First.Types, ", "), r.Second.Pokemon, strings.Join(r.Second.Types, ", "))
+	tw := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	for _, side := range []versusSideResult{r.First, r.Second} {
+		for _, attack := range side.Attacks {
+			fmt.Fprintf(tw, "  %s's %s moves:\tx%s\t%s\n", side.Pokemon, attack.Type, multiplier(attack.Multiplier), effectLabel(attack.Multiplier))
+		}
+	}
+	tw.Flush()
+	if r.Advantage != "" {
+		fmt.Fprintf(&b, "%s has the type advantage.\n", r.Advantage)
+	} else {
+		b.WriteString("Neither has the type advantage.\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func effectLabel(m float64) string {
+	switch {
+	case m == 0:
+		return "no effect"
+	case m > 1:
+		return "super effective"
+	case m < 1:
+		return "not very effective"
+	}
+	return "neutral"
+}
+
+func article(noun string) string {
+	if noun != "" && strings.ContainsRune("AEIOUaeiou", rune(noun[0])) {
+		return "an"
+	}
+	return "a"
+}